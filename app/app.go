@@ -35,8 +35,10 @@ import (
 
 	knfu "github.com/essentialkaos/ek/v13/knf/united"
 	knfv "github.com/essentialkaos/ek/v13/knf/validators"
+	knfc "github.com/essentialkaos/ek/v13/knf/validators/cron"
 	knff "github.com/essentialkaos/ek/v13/knf/validators/fs"
 	knfn "github.com/essentialkaos/ek/v13/knf/validators/network"
+	knft "github.com/essentialkaos/ek/v13/knf/validators/time"
 
 	"go.uber.org/automaxprocs/maxprocs"
 )
@@ -58,6 +60,12 @@ const (
 	OPT_INTERACTIVE = "I:interactive"
 	OPT_SERVER      = "S:server"
 	OPT_FORCE       = "F:force"
+	OPT_JSON        = "j:json"
+	OPT_LIMIT       = "L:limit"
+	OPT_TRIGGER     = "T:trigger"
+	OPT_OUTCOME     = "O:outcome"
+	OPT_SINCE       = "s:since"
+	OPT_WATCH       = "W:watch"
 	OPT_NO_COLOR    = "nc:no-color"
 	OPT_HELP        = "h:help"
 	OPT_VER         = "v:version"
@@ -72,12 +80,40 @@ const (
 	ACCESS_EMAIL   = "access:email"
 	ACCESS_API_KEY = "access:api-key"
 
-	SERVER_IP           = "server:ip"
-	SERVER_PORT         = "server:port"
-	SERVER_ACCESS_TOKEN = "server:access-token"
-
-	STORAGE_TYPE           = "storage:type"
-	STORAGE_ENCRYPTION_KEY = "storage:encryption-key"
+	SERVER_IP              = "server:ip"
+	SERVER_PORT            = "server:port"
+	SERVER_ACCESS_TOKEN    = "server:access-token"
+	SERVER_TLS_CERT        = "server:tls-cert"
+	SERVER_TLS_KEY         = "server:tls-key"
+	SERVER_TLS_CLIENT_CA   = "server:tls-client-ca"
+	SERVER_CALLBACK_URL    = "server:callback-url"
+	SERVER_CALLBACK_SECRET = "server:callback-secret"
+	SERVER_GRACE_PERIOD    = "server:grace-period"
+
+	STORAGE_TYPE                  = "storage:type"
+	STORAGE_ENCRYPTION            = "storage:encryption"
+	STORAGE_ENCRYPTION_KEY        = "storage:encryption-key"
+	STORAGE_ENCRYPTION_KEY_ID     = "storage:encryption-key-id"
+	STORAGE_ENCRYPTION_RECIPIENTS = "storage:encryption-recipients"
+	STORAGE_RESUME                = "storage:resume"
+
+	ENCRYPTION_KEYS = "encryption-keys"
+
+	SERVER_TOKENS = "server-tokens"
+	SERVER_CERTS  = "server-certs"
+
+	KMS_PROVIDER = "kms:provider"
+
+	KMS_VAULT_ADDRESS = "kms-vault:address"
+	KMS_VAULT_TOKEN   = "kms-vault:token"
+	KMS_VAULT_MOUNT   = "kms-vault:mount"
+	KMS_VAULT_KEY     = "kms-vault:key"
+
+	KMS_AWS_ENDPOINT   = "kms-aws:endpoint"
+	KMS_AWS_REGION     = "kms-aws:region"
+	KMS_AWS_ACCESS_KEY = "kms-aws:access-key"
+	KMS_AWS_SECRET_KEY = "kms-aws:secret-key"
+	KMS_AWS_KEY_ID     = "kms-aws:key-id"
 
 	STORAGE_FS_PATH = "storage-fs:path"
 	STORAGE_FS_MODE = "storage-fs:mode"
@@ -96,17 +132,69 @@ const (
 	STORAGE_S3_PATH       = "storage-s3:path"
 	STORAGE_S3_PART_SIZE  = "storage-s3:part-size"
 
+	STORAGE_S3_STALE_TIMEOUT = "storage-s3:stale-timeout"
+
 	JIRA_OUTPUT_FILE         = "jira:output-file"
 	JIRA_INCLUDE_ATTACHMENTS = "jira:include-attachments"
 	JIRA_CLOUD_FORMAT        = "jira:cloud-format"
+	JIRA_SCHEDULE            = "jira:schedule"
 
 	CONFLUENCE_OUTPUT_FILE         = "confluence:output-file"
 	CONFLUENCE_INCLUDE_ATTACHMENTS = "confluence:include-attachments"
 	CONFLUENCE_CLOUD_FORMAT        = "confluence:cloud-format"
+	CONFLUENCE_SCHEDULE            = "confluence:schedule"
 
-	UPDOWN_PULSE_WEBHOOK = "updown-pulse:webhook"
+	SCHEDULER_TIMEZONE = "scheduler:timezone"
+	SCHEDULER_JITTER   = "scheduler:jitter"
+	SCHEDULER_CATCH_UP = "scheduler:catch-up"
 
-	TEMP_DIR = "temp:dir"
+	CONCURRENCY_DOWNLOADS = "concurrency:downloads"
+	CONCURRENCY_UPLOADS   = "concurrency:uploads"
+
+	UPDOWN_PULSE_WEBHOOK = "updown-pulse:webhook"
+	UPDOWN_PULSE_EVENTS  = "updown-pulse:events"
+
+	NOTIFY_WEBHOOK_URL      = "notify-webhook:url"
+	NOTIFY_WEBHOOK_TEMPLATE = "notify-webhook:template"
+	NOTIFY_WEBHOOK_TIMEOUT  = "notify-webhook:timeout"
+	NOTIFY_WEBHOOK_EVENTS   = "notify-webhook:events"
+
+	NOTIFY_SMTP_HOST            = "notify-smtp:host"
+	NOTIFY_SMTP_PORT            = "notify-smtp:port"
+	NOTIFY_SMTP_TLS             = "notify-smtp:tls"
+	NOTIFY_SMTP_USERNAME        = "notify-smtp:username"
+	NOTIFY_SMTP_PASSWORD        = "notify-smtp:password"
+	NOTIFY_SMTP_FROM            = "notify-smtp:from"
+	NOTIFY_SMTP_TO              = "notify-smtp:to"
+	NOTIFY_SMTP_SUCCESS_TO      = "notify-smtp:success-to"
+	NOTIFY_SMTP_FAILURE_TO      = "notify-smtp:failure-to"
+	NOTIFY_SMTP_SUCCESS_SUBJECT = "notify-smtp:success-subject"
+	NOTIFY_SMTP_FAILURE_SUBJECT = "notify-smtp:failure-subject"
+	NOTIFY_SMTP_TIMEOUT         = "notify-smtp:timeout"
+	NOTIFY_SMTP_EVENTS          = "notify-smtp:events"
+
+	NOTIFY_HEALTHCHECKS_URL     = "notify-healthchecks:url"
+	NOTIFY_HEALTHCHECKS_TIMEOUT = "notify-healthchecks:timeout"
+	NOTIFY_HEALTHCHECKS_EVENTS  = "notify-healthchecks:events"
+
+	NOTIFY_CHAT_TYPE    = "notify-chat:type"
+	NOTIFY_CHAT_WEBHOOK = "notify-chat:webhook"
+	NOTIFY_CHAT_API_URL = "notify-chat:api-url"
+	NOTIFY_CHAT_TOKEN   = "notify-chat:token"
+	NOTIFY_CHAT_CHANNEL = "notify-chat:channel"
+	NOTIFY_CHAT_LINK    = "notify-chat:link"
+	NOTIFY_CHAT_DIGEST  = "notify-chat:digest"
+	NOTIFY_CHAT_TIMEOUT = "notify-chat:timeout"
+	NOTIFY_CHAT_EVENTS  = "notify-chat:events"
+
+	METRICS_TEXTFILE_DIR    = "metrics:textfile-dir"
+	METRICS_PUSHGATEWAY_URL = "metrics:pushgateway-url"
+
+	TEMP_DIR            = "temp:dir"
+	TEMP_ENCRYPT        = "temp:encrypt"
+	TEMP_ENCRYPTION_KEY = "temp:encryption-key"
+
+	DATA_DIR = "data:dir"
 
 	LOG_DIR    = "log:dir"
 	LOG_FILE   = "log:file"
@@ -120,6 +208,17 @@ const (
 const (
 	TARGET_JIRA       = "jira"
 	TARGET_CONFLUENCE = "confluence"
+	TARGET_ALL        = "all"
+)
+
+const (
+	CMD_DECRYPT     = "decrypt"
+	CMD_HEALTHCHECK = "healthcheck"
+	CMD_HISTORY     = "history"
+	CMD_STATUS      = "status"
+	CMD_LIST        = "list"
+	CMD_REINDEX     = "reindex"
+	CMD_CHECK       = "check"
 )
 
 const (
@@ -128,6 +227,17 @@ const (
 	STORAGE_S3   = "s3"
 )
 
+const (
+	ENCRYPTION_KATANA   = "katana"
+	ENCRYPTION_AGE      = "age"
+	ENCRYPTION_ENVELOPE = "envelope"
+)
+
+const (
+	KMS_VAULT = "vault"
+	KMS_AWS   = "aws"
+)
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 // optMap contains information about all supported options
@@ -136,6 +246,12 @@ var optMap = options.Map{
 	OPT_FORCE:       {Type: options.BOOL},
 	OPT_INTERACTIVE: {Type: options.BOOL},
 	OPT_SERVER:      {Type: options.BOOL},
+	OPT_JSON:        {Type: options.BOOL},
+	OPT_LIMIT:       {Type: options.INT, Value: 20, Min: 0},
+	OPT_TRIGGER:     {},
+	OPT_OUTCOME:     {},
+	OPT_SINCE:       {},
+	OPT_WATCH:       {Type: options.BOOL},
 	OPT_NO_COLOR:    {Type: options.BOOL},
 	OPT_HELP:        {Type: options.MIXED},
 	OPT_VER:         {Type: options.MIXED},
@@ -148,6 +264,9 @@ var optMap = options.Map{
 // temp is temp data manager
 var temp *tmp.Temp
 
+// knfConfig is loaded configuration file
+var knfConfig *knf.Config
+
 // color tags for app name and version
 var colorTagApp, colorTagVer string
 
@@ -193,11 +312,47 @@ func Run(gitRev string, gomod []byte) {
 		os.Exit(0)
 	}
 
-	err := errors.Chain(
-		loadConfig,
-		validateConfig,
-		setupLogger,
-	)
+	err := loadConfig()
+
+	if err != nil {
+		terminal.Error(err)
+		os.Exit(1)
+	}
+
+	// Preflight check reports all configuration errors by itself, so it must
+	// be started before configuration validation
+	if args.Get(0).Is(CMD_CHECK) {
+		os.Exit(runPreflightCheck())
+	}
+
+	err = validateConfig()
+
+	if err != nil {
+		terminal.Error(err)
+		os.Exit(1)
+	}
+
+	if args.Get(0).Is(CMD_HEALTHCHECK) {
+		os.Exit(runHealthcheck())
+	}
+
+	if args.Get(0).Is(CMD_HISTORY) {
+		os.Exit(printHistory(args))
+	}
+
+	if args.Get(0).Is(CMD_STATUS) {
+		os.Exit(printStatus(args))
+	}
+
+	if args.Get(0).Is(CMD_LIST) {
+		os.Exit(printCatalog(args))
+	}
+
+	if args.Get(0).Is(CMD_REINDEX) {
+		os.Exit(reindexCatalog(args))
+	}
+
+	err = setupLogger()
 
 	if err != nil {
 		terminal.Error(err)
@@ -217,6 +372,8 @@ func Run(gitRev string, gomod []byte) {
 		setupGoMaxProcs,
 		setupTemp,
 		setupReq,
+		setupNotifiers,
+		setupHistory,
 	)
 
 	if err != nil {
@@ -224,9 +381,12 @@ func Run(gitRev string, gomod []byte) {
 		os.Exit(1)
 	}
 
-	if options.GetB(OPT_SERVER) {
+	switch {
+	case options.GetB(OPT_SERVER):
 		err = startServer()
-	} else {
+	case args.Get(0).Is(CMD_DECRYPT):
+		err = decryptBackup(args)
+	default:
 		err = startApp(args)
 	}
 
@@ -271,16 +431,38 @@ func addExtraOptions(m options.Map) {
 	knfu.AddOptions(m,
 		ACCESS_ACCOUNT, ACCESS_EMAIL, ACCESS_API_KEY,
 		SERVER_IP, SERVER_PORT, SERVER_ACCESS_TOKEN,
-		STORAGE_TYPE, STORAGE_ENCRYPTION_KEY,
+		SERVER_TLS_CERT, SERVER_TLS_KEY, SERVER_TLS_CLIENT_CA,
+		SERVER_CALLBACK_URL, SERVER_CALLBACK_SECRET, SERVER_GRACE_PERIOD,
+		STORAGE_TYPE, STORAGE_ENCRYPTION, STORAGE_ENCRYPTION_KEY,
+		STORAGE_ENCRYPTION_KEY_ID, STORAGE_ENCRYPTION_RECIPIENTS, STORAGE_RESUME,
+		KMS_PROVIDER, KMS_VAULT_ADDRESS, KMS_VAULT_TOKEN, KMS_VAULT_MOUNT, KMS_VAULT_KEY,
+		KMS_AWS_ENDPOINT, KMS_AWS_REGION, KMS_AWS_ACCESS_KEY, KMS_AWS_SECRET_KEY,
+		KMS_AWS_KEY_ID,
 		STORAGE_FS_PATH, STORAGE_FS_MODE,
 		STORAGE_SFTP_HOST, STORAGE_SFTP_USER, STORAGE_SFTP_KEY,
 		STORAGE_SFTP_PATH, STORAGE_SFTP_MODE,
 		STORAGE_S3_HOST, STORAGE_S3_ACCESS_KEY, STORAGE_S3_SECRET_KEY,
 		STORAGE_S3_BUCKET, STORAGE_S3_PATH, STORAGE_S3_PART_SIZE,
+		STORAGE_S3_STALE_TIMEOUT,
 		JIRA_OUTPUT_FILE, JIRA_INCLUDE_ATTACHMENTS, JIRA_CLOUD_FORMAT,
+		JIRA_SCHEDULE,
 		CONFLUENCE_OUTPUT_FILE, CONFLUENCE_INCLUDE_ATTACHMENTS, CONFLUENCE_CLOUD_FORMAT,
-		UPDOWN_PULSE_WEBHOOK,
-		TEMP_DIR,
+		CONFLUENCE_SCHEDULE,
+		SCHEDULER_TIMEZONE, SCHEDULER_JITTER, SCHEDULER_CATCH_UP,
+		CONCURRENCY_DOWNLOADS, CONCURRENCY_UPLOADS,
+		UPDOWN_PULSE_WEBHOOK, UPDOWN_PULSE_EVENTS,
+		NOTIFY_WEBHOOK_URL, NOTIFY_WEBHOOK_TEMPLATE, NOTIFY_WEBHOOK_TIMEOUT,
+		NOTIFY_WEBHOOK_EVENTS,
+		NOTIFY_SMTP_HOST, NOTIFY_SMTP_PORT, NOTIFY_SMTP_TLS, NOTIFY_SMTP_USERNAME,
+		NOTIFY_SMTP_PASSWORD, NOTIFY_SMTP_FROM, NOTIFY_SMTP_TO, NOTIFY_SMTP_SUCCESS_TO,
+		NOTIFY_SMTP_FAILURE_TO, NOTIFY_SMTP_SUCCESS_SUBJECT, NOTIFY_SMTP_FAILURE_SUBJECT,
+		NOTIFY_SMTP_TIMEOUT, NOTIFY_SMTP_EVENTS,
+		NOTIFY_HEALTHCHECKS_URL, NOTIFY_HEALTHCHECKS_TIMEOUT, NOTIFY_HEALTHCHECKS_EVENTS,
+		NOTIFY_CHAT_TYPE, NOTIFY_CHAT_WEBHOOK, NOTIFY_CHAT_API_URL, NOTIFY_CHAT_TOKEN,
+		NOTIFY_CHAT_CHANNEL, NOTIFY_CHAT_LINK, NOTIFY_CHAT_DIGEST, NOTIFY_CHAT_TIMEOUT,
+		NOTIFY_CHAT_EVENTS,
+		METRICS_TEXTFILE_DIR, METRICS_PUSHGATEWAY_URL,
+		TEMP_DIR, TEMP_ENCRYPT, TEMP_ENCRYPTION_KEY, DATA_DIR,
 		LOG_FORMAT, LOG_LEVEL,
 	)
 }
@@ -300,6 +482,8 @@ func loadConfig() error {
 		return fmt.Errorf("Can't load configuration: %w", err)
 	}
 
+	knfConfig = config
+
 	if !container.IsContainer() {
 		knfu.Combine(config)
 	} else {
@@ -307,16 +491,38 @@ func loadConfig() error {
 			config,
 			ACCESS_ACCOUNT, ACCESS_EMAIL, ACCESS_API_KEY,
 			SERVER_IP, SERVER_PORT, SERVER_ACCESS_TOKEN,
-			STORAGE_TYPE, STORAGE_ENCRYPTION_KEY,
+			SERVER_TLS_CERT, SERVER_TLS_KEY, SERVER_TLS_CLIENT_CA,
+			SERVER_CALLBACK_URL, SERVER_CALLBACK_SECRET, SERVER_GRACE_PERIOD,
+			STORAGE_TYPE, STORAGE_ENCRYPTION, STORAGE_ENCRYPTION_KEY,
+			STORAGE_ENCRYPTION_KEY_ID, STORAGE_ENCRYPTION_RECIPIENTS, STORAGE_RESUME,
+			KMS_PROVIDER, KMS_VAULT_ADDRESS, KMS_VAULT_TOKEN, KMS_VAULT_MOUNT, KMS_VAULT_KEY,
+			KMS_AWS_ENDPOINT, KMS_AWS_REGION, KMS_AWS_ACCESS_KEY, KMS_AWS_SECRET_KEY,
+			KMS_AWS_KEY_ID,
 			STORAGE_FS_PATH, STORAGE_FS_MODE,
 			STORAGE_SFTP_HOST, STORAGE_SFTP_USER, STORAGE_SFTP_KEY,
 			STORAGE_SFTP_PATH, STORAGE_SFTP_MODE,
 			STORAGE_S3_HOST, STORAGE_S3_REGION, STORAGE_S3_ACCESS_KEY,
 			STORAGE_S3_SECRET_KEY, STORAGE_S3_BUCKET, STORAGE_S3_PATH, STORAGE_S3_PART_SIZE,
+			STORAGE_S3_STALE_TIMEOUT,
 			JIRA_OUTPUT_FILE, JIRA_INCLUDE_ATTACHMENTS, JIRA_CLOUD_FORMAT,
+			JIRA_SCHEDULE,
 			CONFLUENCE_OUTPUT_FILE, CONFLUENCE_INCLUDE_ATTACHMENTS, CONFLUENCE_CLOUD_FORMAT,
-			UPDOWN_PULSE_WEBHOOK,
-			TEMP_DIR,
+			CONFLUENCE_SCHEDULE,
+			SCHEDULER_TIMEZONE, SCHEDULER_JITTER, SCHEDULER_CATCH_UP,
+			CONCURRENCY_DOWNLOADS, CONCURRENCY_UPLOADS,
+			UPDOWN_PULSE_WEBHOOK, UPDOWN_PULSE_EVENTS,
+			NOTIFY_WEBHOOK_URL, NOTIFY_WEBHOOK_TEMPLATE, NOTIFY_WEBHOOK_TIMEOUT,
+			NOTIFY_WEBHOOK_EVENTS,
+			NOTIFY_SMTP_HOST, NOTIFY_SMTP_PORT, NOTIFY_SMTP_TLS, NOTIFY_SMTP_USERNAME,
+			NOTIFY_SMTP_PASSWORD, NOTIFY_SMTP_FROM, NOTIFY_SMTP_TO, NOTIFY_SMTP_SUCCESS_TO,
+			NOTIFY_SMTP_FAILURE_TO, NOTIFY_SMTP_SUCCESS_SUBJECT, NOTIFY_SMTP_FAILURE_SUBJECT,
+			NOTIFY_SMTP_TIMEOUT, NOTIFY_SMTP_EVENTS,
+			NOTIFY_HEALTHCHECKS_URL, NOTIFY_HEALTHCHECKS_TIMEOUT, NOTIFY_HEALTHCHECKS_EVENTS,
+			NOTIFY_CHAT_TYPE, NOTIFY_CHAT_WEBHOOK, NOTIFY_CHAT_API_URL, NOTIFY_CHAT_TOKEN,
+			NOTIFY_CHAT_CHANNEL, NOTIFY_CHAT_LINK, NOTIFY_CHAT_DIGEST, NOTIFY_CHAT_TIMEOUT,
+			NOTIFY_CHAT_EVENTS,
+			METRICS_TEXTFILE_DIR, METRICS_PUSHGATEWAY_URL,
+			TEMP_DIR, TEMP_ENCRYPT, TEMP_ENCRYPTION_KEY, DATA_DIR,
 			LOG_DIR, LOG_FILE, LOG_MODE, LOG_LEVEL,
 		)
 	}
@@ -326,6 +532,17 @@ func loadConfig() error {
 
 // validateConfig validates configuration file values
 func validateConfig() error {
+	errs := knfu.Validate(getConfigValidators())
+
+	if !errs.IsEmpty() {
+		return errs.First()
+	}
+
+	return nil
+}
+
+// getConfigValidators returns validators for configuration file values
+func getConfigValidators() knf.Validators {
 	validators := knf.Validators{
 		{ACCESS_ACCOUNT, knfv.Set, nil},
 		{ACCESS_EMAIL, knfv.Set, nil},
@@ -336,9 +553,35 @@ func validateConfig() error {
 			STORAGE_FS, STORAGE_SFTP, STORAGE_S3,
 		}},
 
+		{STORAGE_ENCRYPTION, knfv.SetToAnyIgnoreCase, []string{
+			"", ENCRYPTION_KATANA, ENCRYPTION_AGE, ENCRYPTION_ENVELOPE,
+		}},
+
+		{STORAGE_RESUME, knfv.TypeBool, nil},
+
+		{KMS_PROVIDER, knfv.SetToAnyIgnoreCase, []string{"", KMS_VAULT, KMS_AWS}},
+
+		{JIRA_SCHEDULE, knfc.Expression, nil},
+		{CONFLUENCE_SCHEDULE, knfc.Expression, nil},
+		{SCHEDULER_TIMEZONE, knft.Timezone, nil},
+		{SCHEDULER_JITTER, knfv.TypeDur, nil},
+		{SCHEDULER_CATCH_UP, knfv.TypeBool, nil},
+
+		{CONCURRENCY_DOWNLOADS, knfv.TypeNum, nil},
+		{CONCURRENCY_DOWNLOADS, knfv.InRange, knfv.Range{From: 1, To: 16}},
+		{CONCURRENCY_UPLOADS, knfv.TypeNum, nil},
+		{CONCURRENCY_UPLOADS, knfv.InRange, knfv.Range{From: 1, To: 16}},
+
 		{UPDOWN_PULSE_WEBHOOK, knfn.URL, nil},
+		{NOTIFY_WEBHOOK_URL, knfn.URL, nil},
+		{NOTIFY_WEBHOOK_TIMEOUT, knfv.TypeDur, nil},
+		{NOTIFY_HEALTHCHECKS_URL, knfn.URL, nil},
+		{NOTIFY_HEALTHCHECKS_TIMEOUT, knfv.TypeDur, nil},
+
+		{METRICS_PUSHGATEWAY_URL, knfn.URL, nil},
 
 		{TEMP_DIR, knff.Perms, "DWRX"},
+		{TEMP_ENCRYPT, knfv.TypeBool, nil},
 
 		{LOG_FORMAT, knfv.SetToAnyIgnoreCase, []string{"", "text", "json"}},
 		{LOG_LEVEL, knfv.SetToAnyIgnoreCase, log.Levels()},
@@ -368,6 +611,45 @@ func validateConfig() error {
 			{STORAGE_S3_PART_SIZE, knfv.TypeSize, nil},
 			{STORAGE_S3_PART_SIZE, knfv.SizeGreater, 1 * 1024 * 1024},
 			{STORAGE_S3_PART_SIZE, knfv.SizeLess, 100 * 1024 * 1024},
+			{STORAGE_S3_STALE_TIMEOUT, knfv.TypeDur, nil},
+		},
+	)
+
+	validators = validators.AddIf(knfu.GetS(NOTIFY_WEBHOOK_TEMPLATE) != "",
+		knf.Validators{
+			{NOTIFY_WEBHOOK_TEMPLATE, knff.Perms, "FRS"},
+		},
+	)
+
+	validators = validators.AddIf(knfu.GetS(NOTIFY_SMTP_HOST) != "",
+		knf.Validators{
+			{NOTIFY_SMTP_FROM, knfv.Set, nil},
+			{NOTIFY_SMTP_FROM, knfn.Mail, nil},
+			{NOTIFY_SMTP_PORT, knfn.Port, nil},
+			{NOTIFY_SMTP_TLS, knfv.SetToAnyIgnoreCase, []string{"", "none", "starttls", "tls"}},
+			{NOTIFY_SMTP_TIMEOUT, knfv.TypeDur, nil},
+		},
+	)
+
+	validators = validators.AddIf(knfu.GetS(NOTIFY_CHAT_TYPE) != "",
+		knf.Validators{
+			{NOTIFY_CHAT_TYPE, knfv.SetToAnyIgnoreCase, []string{"slack", "mattermost"}},
+			{NOTIFY_CHAT_WEBHOOK, knfn.URL, nil},
+			{NOTIFY_CHAT_API_URL, knfn.URL, nil},
+			{NOTIFY_CHAT_DIGEST, knfv.TypeBool, nil},
+			{NOTIFY_CHAT_TIMEOUT, knfv.TypeDur, nil},
+		},
+	)
+
+	validators = validators.AddIf(knfu.GetS(METRICS_TEXTFILE_DIR) != "",
+		knf.Validators{
+			{METRICS_TEXTFILE_DIR, knff.Perms, "DWX"},
+		},
+	)
+
+	validators = validators.AddIf(knfu.GetS(DATA_DIR) != "",
+		knf.Validators{
+			{DATA_DIR, knff.Perms, "DWX"},
 		},
 	)
 
@@ -375,6 +657,105 @@ func validateConfig() error {
 		knf.Validators{
 			{SERVER_IP, knfn.IP, nil},
 			{SERVER_PORT, knfn.Port, nil},
+			{SERVER_GRACE_PERIOD, knfv.TypeDur, nil},
+		},
+	)
+
+	validators = validators.AddIf(
+		strings.ToLower(knfu.GetS(STORAGE_ENCRYPTION)) == ENCRYPTION_KATANA &&
+			knfu.GetS(STORAGE_ENCRYPTION_KEY_ID) == "",
+		knf.Validators{
+			{STORAGE_ENCRYPTION_KEY, knfv.Set, nil},
+		},
+	)
+
+	// Encrypted uploads can't be resumed, so resuming can't be explicitly
+	// enabled together with encryption
+	validators = validators.AddIf(getEncryptionType() != "" && knfu.GetS(STORAGE_RESUME) != "",
+		knf.Validators{
+			{STORAGE_RESUME, knfv.NotEquals, true},
+		},
+	)
+
+	validators = validators.AddIf(strings.ToLower(knfu.GetS(STORAGE_ENCRYPTION)) == ENCRYPTION_ENVELOPE,
+		knf.Validators{
+			{KMS_PROVIDER, knfv.Set, nil},
+		},
+	)
+
+	validators = validators.AddIf(strings.ToLower(knfu.GetS(KMS_PROVIDER)) == KMS_VAULT,
+		knf.Validators{
+			{KMS_VAULT_ADDRESS, knfv.Set, nil},
+			{KMS_VAULT_ADDRESS, knfn.URL, nil},
+			{KMS_VAULT_TOKEN, knfv.Set, nil},
+			{KMS_VAULT_KEY, knfv.Set, nil},
+		},
+	)
+
+	validators = validators.AddIf(strings.ToLower(knfu.GetS(KMS_PROVIDER)) == KMS_AWS,
+		knf.Validators{
+			{KMS_AWS_ENDPOINT, knfn.URL, nil},
+			{KMS_AWS_REGION, knfv.Set, nil},
+			{KMS_AWS_ACCESS_KEY, knfv.Set, nil},
+			{KMS_AWS_SECRET_KEY, knfv.Set, nil},
+			{KMS_AWS_KEY_ID, knfv.Set, nil},
+		},
+	)
+
+	validators = validators.AddIf(knfu.GetS(STORAGE_ENCRYPTION_KEY_ID) != "",
+		knf.Validators{
+			{STORAGE_ENCRYPTION_KEY_ID, knfv.SetToAny, getEncryptionKeyIDs()},
+		},
+	)
+
+	validators = validators.AddIf(options.GetB(OPT_SERVER) && knfu.GetS(SERVER_TLS_CERT) != "",
+		knf.Validators{
+			{SERVER_TLS_CERT, knff.Perms, "FRS"},
+			{SERVER_TLS_KEY, knfv.Set, nil},
+			{SERVER_TLS_KEY, knff.Perms, "FRS"},
+		},
+	)
+
+	validators = validators.AddIf(
+		options.GetB(OPT_SERVER) && (knfu.GetS(SERVER_TLS_CLIENT_CA) != "" || len(getServerCertSubjects()) != 0),
+		knf.Validators{
+			{SERVER_TLS_CERT, knfv.Set, nil},
+			{SERVER_TLS_CLIENT_CA, knfv.Set, nil},
+			{SERVER_TLS_CLIENT_CA, knff.Perms, "FRS"},
+		},
+	)
+
+	validators = validators.AddIf(options.GetB(OPT_SERVER) && knfu.GetS(SERVER_CALLBACK_URL) != "",
+		knf.Validators{
+			{SERVER_CALLBACK_URL, knfn.URL, nil},
+			{SERVER_CALLBACK_SECRET, knfv.Set, nil},
+		},
+	)
+
+	validators = validators.AddIf(options.GetB(OPT_SERVER) && knfu.GetS(SERVER_CALLBACK_SECRET) != "",
+		knf.Validators{
+			{SERVER_CALLBACK_SECRET, knfv.LenLonger, 16},
+		},
+	)
+
+	if options.GetB(OPT_SERVER) {
+		for _, client := range getServerTokenNames() {
+			validators = validators.Add(knf.Validators{
+				{SERVER_TOKENS + ":" + client, knfv.Set, nil},
+			})
+		}
+	}
+
+	for _, keyID := range getEncryptionKeyIDs() {
+		validators = validators.Add(knf.Validators{
+			{ENCRYPTION_KEYS + ":" + keyID, knfv.LenLonger, 16},
+			{ENCRYPTION_KEYS + ":" + keyID, knfv.LenShorter, 96},
+		})
+	}
+
+	validators = validators.AddIf(strings.ToLower(knfu.GetS(STORAGE_ENCRYPTION)) == ENCRYPTION_AGE,
+		knf.Validators{
+			{STORAGE_ENCRYPTION_RECIPIENTS, knfv.Set, nil},
 		},
 	)
 
@@ -385,13 +766,14 @@ func validateConfig() error {
 		},
 	)
 
-	errs := knfu.Validate(validators)
-
-	if !errs.IsEmpty() {
-		return errs.First()
-	}
+	validators = validators.AddIf(knfu.GetS(TEMP_ENCRYPTION_KEY) != "",
+		knf.Validators{
+			{TEMP_ENCRYPTION_KEY, knfv.LenLonger, 16},
+			{TEMP_ENCRYPTION_KEY, knfv.LenShorter, 96},
+		},
+	)
 
-	return nil
+	return validators
 }
 
 // setupLogger configures logger subsystem
@@ -551,18 +933,32 @@ func addUnitedOption(info *usage.Info, prop, desc, value string) {
 func genUsage(section string) *usage.Info {
 	info := usage.NewInfo(
 		"",
-		fmt.Sprintf("{%s}", strings.Join([]string{
-			TARGET_JIRA, TARGET_CONFLUENCE,
+		fmt.Sprintf("{%s}…", strings.Join([]string{
+			TARGET_JIRA, TARGET_CONFLUENCE, TARGET_ALL,
 		}, "|")),
 	)
 
 	info.WrapLen = 100
 	info.AppNameColorTag = colorTagApp
 
+	info.AddCommand(CMD_DECRYPT, "Decrypt backup file using key from backup metadata", "file", "output")
+	info.AddCommand(CMD_HEALTHCHECK, "Check if server is ready to handle requests")
+	info.AddCommand(CMD_HISTORY, "Show history of backup runs", "?target")
+	info.AddCommand(CMD_STATUS, "Show state of backup on Atlassian side", "target")
+	info.AddCommand(CMD_LIST, "Show backups from storage catalog", "?target")
+	info.AddCommand(CMD_REINDEX, "Rebuild storage catalog using storage listing", "?target")
+	info.AddCommand(CMD_CHECK, "Check configuration, credentials and storage connectivity")
+
 	info.AddOption(OPT_CONFIG, "Path to configuration file", "file")
 	info.AddOption(OPT_INTERACTIVE, "Interactive mode")
 	info.AddOption(OPT_SERVER, "Server mode")
 	info.AddOption(OPT_FORCE, "Force backup generation")
+	info.AddOption(OPT_JSON, "Print data in JSON format")
+	info.AddOption(OPT_LIMIT, "Max number of shown history records {s-}(default: 20){!}", "num")
+	info.AddOption(OPT_TRIGGER, "Filter history by trigger", "cli/cron/server")
+	info.AddOption(OPT_OUTCOME, "Filter history by outcome", "success/failure")
+	info.AddOption(OPT_SINCE, "Filter history by period {s-}(e.g. 7d){!}", "period")
+	info.AddOption(OPT_WATCH, "Follow backup progress until task is finished")
 	info.AddOption(OPT_NO_COLOR, "Disable colors in output")
 	info.AddOption(OPT_HELP, "Show this help message")
 	info.AddOption(OPT_VER, "Show version")
@@ -574,8 +970,28 @@ func genUsage(section string) *usage.Info {
 		addUnitedOption(info, SERVER_IP, "HTTP server IP", "ip")
 		addUnitedOption(info, SERVER_PORT, "HTTP server port", "port")
 		addUnitedOption(info, SERVER_ACCESS_TOKEN, "HTTP access token", "token")
+		addUnitedOption(info, SERVER_TLS_CERT, "Path to TLS certificate", "file")
+		addUnitedOption(info, SERVER_TLS_KEY, "Path to TLS private key", "file")
+		addUnitedOption(info, SERVER_TLS_CLIENT_CA, "Path to CA certificate for client certificates", "file")
+		addUnitedOption(info, SERVER_CALLBACK_URL, "Default URL for job completion callbacks", "url")
+		addUnitedOption(info, SERVER_CALLBACK_SECRET, "Secret for signing callback payloads", "secret")
+		addUnitedOption(info, SERVER_GRACE_PERIOD, "Time to wait for running jobs on shutdown", "duration")
 		addUnitedOption(info, STORAGE_TYPE, "Storage type", "fs/sftp/s3")
+		addUnitedOption(info, STORAGE_ENCRYPTION, "Data encryption type", "katana/age/envelope")
 		addUnitedOption(info, STORAGE_ENCRYPTION_KEY, "Data encryption key", "key")
+		addUnitedOption(info, STORAGE_ENCRYPTION_KEY_ID, "ID of named encryption key for new backups", "id")
+		addUnitedOption(info, STORAGE_ENCRYPTION_RECIPIENTS, "Age recipients or files with recipients", "recipients")
+		addUnitedOption(info, STORAGE_RESUME, "Resume interrupted uploads", "yes/no")
+		addUnitedOption(info, KMS_PROVIDER, "KMS provider for envelope encryption", "vault/aws")
+		addUnitedOption(info, KMS_VAULT_ADDRESS, "Vault address", "url")
+		addUnitedOption(info, KMS_VAULT_TOKEN, "Vault token", "token")
+		addUnitedOption(info, KMS_VAULT_MOUNT, "Vault Transit secrets engine mount path", "path")
+		addUnitedOption(info, KMS_VAULT_KEY, "Vault Transit key name", "name")
+		addUnitedOption(info, KMS_AWS_ENDPOINT, "AWS KMS-compatible API endpoint", "url")
+		addUnitedOption(info, KMS_AWS_REGION, "AWS KMS region", "region")
+		addUnitedOption(info, KMS_AWS_ACCESS_KEY, "AWS KMS access key ID", "id")
+		addUnitedOption(info, KMS_AWS_SECRET_KEY, "AWS KMS secret access key", "key")
+		addUnitedOption(info, KMS_AWS_KEY_ID, "AWS KMS key ID or ARN", "id")
 		addUnitedOption(info, STORAGE_FS_PATH, "Path on system for backups", "path")
 		addUnitedOption(info, STORAGE_FS_MODE, "File mode on system", "mode")
 		addUnitedOption(info, STORAGE_SFTP_HOST, "SFTP host", "host")
@@ -590,19 +1006,69 @@ func genUsage(section string) *usage.Info {
 		addUnitedOption(info, STORAGE_S3_BUCKET, "S3 bucket", "name")
 		addUnitedOption(info, STORAGE_S3_PATH, "Path for backups", "path")
 		addUnitedOption(info, STORAGE_S3_PART_SIZE, "Uploading part size", "size")
+		addUnitedOption(info, STORAGE_S3_STALE_TIMEOUT, "Timeout for aborting stale multipart uploads", "duration")
 		addUnitedOption(info, JIRA_OUTPUT_FILE, "Jira backup output file name template", "template")
 		addUnitedOption(info, JIRA_INCLUDE_ATTACHMENTS, "Include attachments to Jira backup", "yes/no")
 		addUnitedOption(info, JIRA_CLOUD_FORMAT, "Create Jira backup for Cloud", "yes/no")
+		addUnitedOption(info, JIRA_SCHEDULE, "Jira backup schedule in server mode", "cron-expr")
 		addUnitedOption(info, CONFLUENCE_OUTPUT_FILE, "Confluence backup output file name template", "template")
 		addUnitedOption(info, CONFLUENCE_INCLUDE_ATTACHMENTS, "Include attachments to Confluence backup", "yes/no")
 		addUnitedOption(info, CONFLUENCE_CLOUD_FORMAT, "Create Confluence backup for Cloud", "yes/no")
+		addUnitedOption(info, CONFLUENCE_SCHEDULE, "Confluence backup schedule in server mode", "cron-expr")
+		addUnitedOption(info, SCHEDULER_TIMEZONE, "Time zone for backup schedules", "tz")
+		addUnitedOption(info, SCHEDULER_JITTER, "Max random delay before scheduled backup", "duration")
+		addUnitedOption(info, SCHEDULER_CATCH_UP, "Run backups missed while server was stopped", "yes/no")
+		addUnitedOption(info, CONCURRENCY_DOWNLOADS, "Max number of concurrent backup downloads", "num")
+		addUnitedOption(info, CONCURRENCY_UPLOADS, "Max number of concurrent backup uploads", "num")
+		addUnitedOption(info, UPDOWN_PULSE_WEBHOOK, "updown.io pulse webhook URL", "url")
+		addUnitedOption(info, UPDOWN_PULSE_EVENTS, "Events for updown.io pulses", "events")
+		addUnitedOption(info, NOTIFY_WEBHOOK_URL, "Notifications webhook URL", "url")
+		addUnitedOption(info, NOTIFY_WEBHOOK_TEMPLATE, "Path to notifications webhook body template", "file")
+		addUnitedOption(info, NOTIFY_WEBHOOK_TIMEOUT, "Notifications webhook request timeout", "duration")
+		addUnitedOption(info, NOTIFY_WEBHOOK_EVENTS, "Events for notifications webhook", "events")
+		addUnitedOption(info, NOTIFY_SMTP_HOST, "SMTP server host", "host")
+		addUnitedOption(info, NOTIFY_SMTP_PORT, "SMTP server port", "port")
+		addUnitedOption(info, NOTIFY_SMTP_TLS, "SMTP connection security", "mode")
+		addUnitedOption(info, NOTIFY_SMTP_USERNAME, "SMTP user name", "name")
+		addUnitedOption(info, NOTIFY_SMTP_PASSWORD, "SMTP user password", "password")
+		addUnitedOption(info, NOTIFY_SMTP_FROM, "Email reports sender address", "email")
+		addUnitedOption(info, NOTIFY_SMTP_TO, "Email reports recipients", "email…")
+		addUnitedOption(info, NOTIFY_SMTP_SUCCESS_TO, "Recipients of success email reports", "email…")
+		addUnitedOption(info, NOTIFY_SMTP_FAILURE_TO, "Recipients of failure email reports", "email…")
+		addUnitedOption(info, NOTIFY_SMTP_SUCCESS_SUBJECT, "Subject of success email reports", "template")
+		addUnitedOption(info, NOTIFY_SMTP_FAILURE_SUBJECT, "Subject of failure email reports", "template")
+		addUnitedOption(info, NOTIFY_SMTP_TIMEOUT, "SMTP connection timeout", "duration")
+		addUnitedOption(info, NOTIFY_SMTP_EVENTS, "Events for email reports", "events")
+		addUnitedOption(info, NOTIFY_HEALTHCHECKS_URL, "Healthchecks ping URL", "url")
+		addUnitedOption(info, NOTIFY_HEALTHCHECKS_TIMEOUT, "Healthchecks ping timeout", "duration")
+		addUnitedOption(info, NOTIFY_HEALTHCHECKS_EVENTS, "Events for healthchecks pings", "events")
+		addUnitedOption(info, NOTIFY_CHAT_TYPE, "Chat type", "type")
+		addUnitedOption(info, NOTIFY_CHAT_WEBHOOK, "Chat incoming webhook URL", "url")
+		addUnitedOption(info, NOTIFY_CHAT_API_URL, "Mattermost server URL", "url")
+		addUnitedOption(info, NOTIFY_CHAT_TOKEN, "Chat API token", "token")
+		addUnitedOption(info, NOTIFY_CHAT_CHANNEL, "Chat channel", "channel")
+		addUnitedOption(info, NOTIFY_CHAT_LINK, "Template of link to backup in storage", "template")
+		addUnitedOption(info, NOTIFY_CHAT_DIGEST, "Send daily digest instead of success messages", "yes/no")
+		addUnitedOption(info, NOTIFY_CHAT_TIMEOUT, "Chat request timeout", "duration")
+		addUnitedOption(info, NOTIFY_CHAT_EVENTS, "Events for chat notifications", "events")
+		addUnitedOption(info, METRICS_TEXTFILE_DIR, "Path to node_exporter textfile collector directory", "path")
+		addUnitedOption(info, METRICS_PUSHGATEWAY_URL, "Pushgateway URL", "url")
 		addUnitedOption(info, TEMP_DIR, "Path to directory for temporary data", "path")
+		addUnitedOption(info, TEMP_ENCRYPT, "Encrypt temporary data", "yes/no")
+		addUnitedOption(info, TEMP_ENCRYPTION_KEY, "Temporary data encryption key", "key")
+		addUnitedOption(info, DATA_DIR, "Path to directory for persistent data", "path")
 		addUnitedOption(info, LOG_FORMAT, "Log format", "text/json")
 		addUnitedOption(info, LOG_LEVEL, "Log level", "level")
 	} else {
 		info.AddExample("jira", "Run Jira data backup")
 		info.AddExample("confluence", "Run Confluence data backup")
 		info.AddExample("jira -I -F", "Run Jira data backup in interactive mode")
+		info.AddExample("jira confluence", "Run Jira and Confluence data backups at the same time")
+		info.AddExample(CMD_STATUS+" confluence --watch", "Follow progress of Confluence backup")
+		info.AddExample(
+			CMD_DECRYPT+" jira-backup-2025-01-01.zip backup.zip",
+			"Decrypt backup file using key from backup metadata",
+		)
 	}
 
 	return info
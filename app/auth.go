@@ -0,0 +1,405 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/log"
+
+	knfu "github.com/essentialkaos/ek/v13/knf/united"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	ACTION_CREATE   = "create"
+	ACTION_DOWNLOAD = "download"
+	ACTION_STATUS   = "status"
+)
+
+const (
+	// AUTH_MAX_FAILURES is max number of failed authentication attempts
+	AUTH_MAX_FAILURES = 5
+
+	// AUTH_FAILURES_PERIOD is period for counting failed authentication attempts
+	AUTH_FAILURES_PERIOD = 5 * time.Minute
+
+	// AUTH_BLOCK_PERIOD is period of blocking client after too many failed attempts
+	AUTH_BLOCK_PERIOD = 15 * time.Minute
+)
+
+// AUTH_MIN_TOKEN_LENGTH is minimal length of named client token
+const AUTH_MIN_TOKEN_LENGTH = 16
+
+// AUTH_ANONYMOUS is name of client used if authentication is disabled
+const AUTH_ANONYMOUS = "anonymous"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// AuthClient contains info about API client
+type AuthClient struct {
+	Name   string
+	Scopes []string
+
+	tokenHash [sha256.Size]byte
+}
+
+// authAttempts contains info about failed authentication attempts
+type authAttempts struct {
+	Count        int
+	First        time.Time
+	BlockedUntil time.Time
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrAuthNoToken      = fmt.Errorf("Access token is empty")
+	ErrAuthInvalidToken = fmt.Errorf("Invalid access token")
+	ErrAuthBlocked      = fmt.Errorf("Too many failed authentication attempts")
+	ErrAuthForbidden    = fmt.Errorf("Access token has no permission for this action")
+	ErrAuthNoCert       = fmt.Errorf("Client certificate is required")
+	ErrAuthUnknownCert  = fmt.Errorf("Client certificate subject is not allowed")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// authClients is list of API clients
+var authClients []*AuthClient
+
+// certClients is map with API clients identified by certificate subject
+var certClients map[string]*AuthClient
+
+// authFailures contains failed authentication attempts by remote IP
+var authFailures = map[string]*authAttempts{}
+
+// authMu is mutex for failed attempts map
+var authMu sync.Mutex
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// loadAuthClients loads API clients from configuration
+func loadAuthClients() error {
+	authClients = nil
+
+	if knfu.GetS(SERVER_ACCESS_TOKEN) != "" {
+		authClients = append(authClients, &AuthClient{
+			Name:      "default",
+			Scopes:    []string{"*:*"},
+			tokenHash: sha256.Sum256([]byte(knfu.GetS(SERVER_ACCESS_TOKEN))),
+		})
+	}
+
+	for _, name := range getServerTokenNames() {
+		fields := strings.Fields(knfu.GetS(SERVER_TOKENS + ":" + name))
+
+		if len(fields) == 0 {
+			return fmt.Errorf("Token for client %q is empty", name)
+		}
+
+		if len(fields[0]) < AUTH_MIN_TOKEN_LENGTH {
+			return fmt.Errorf(
+				"Token for client %q is too short (must be at least %d symbols)",
+				name, AUTH_MIN_TOKEN_LENGTH,
+			)
+		}
+
+		scopes, err := parseAuthScopes(fields[1:])
+
+		if err != nil {
+			return fmt.Errorf("Invalid scope for client %q: %w", name, err)
+		}
+
+		authClients = append(authClients, &AuthClient{
+			Name:      name,
+			Scopes:    scopes,
+			tokenHash: sha256.Sum256([]byte(fields[0])),
+		})
+	}
+
+	certClients = map[string]*AuthClient{}
+
+	for _, subject := range getServerCertSubjects() {
+		scopes, err := parseAuthScopes(strings.Fields(knfu.GetS(SERVER_CERTS + ":" + subject)))
+
+		if err != nil {
+			return fmt.Errorf("Invalid scope for certificate subject %q: %w", subject, err)
+		}
+
+		certClients[subject] = &AuthClient{Name: subject, Scopes: scopes}
+	}
+
+	if len(authClients) == 0 && len(certClients) == 0 {
+		log.Warn("Authentication is disabled: no access tokens configured")
+	}
+
+	return nil
+}
+
+// authorize authenticates request and checks if client has access to given
+// action for given target. If access is denied, error response is written.
+func authorize(rw http.ResponseWriter, r *http.Request, target, action string) (*AuthClient, bool) {
+	client, err := authenticate(r)
+
+	if err == nil && !client.IsAllowed(target, action) {
+		err = ErrAuthForbidden
+	}
+
+	if err == nil {
+		return client, true
+	}
+
+	log.Warn(
+		"Request rejected: %v", err,
+		log.F{"audit", true}, log.F{"remote-ip", getRemoteIP(r)},
+		log.F{"action", action}, log.F{"target", target},
+	)
+
+	switch err {
+	case ErrAuthBlocked:
+		writeErrorResponse(rw, http.StatusTooManyRequests, err)
+	case ErrAuthForbidden, ErrAuthUnknownCert:
+		writeErrorResponse(rw, http.StatusForbidden, err)
+	default:
+		rw.Header().Set("WWW-Authenticate", "Bearer")
+		writeErrorResponse(rw, http.StatusUnauthorized, err)
+	}
+
+	return nil, false
+}
+
+// authenticate finds client for certificate or token from request
+func authenticate(r *http.Request) (*AuthClient, error) {
+	if isMTLSEnabled() {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			return nil, ErrAuthNoCert
+		}
+
+		// If subjects are mapped, client is identified by certificate only
+		if len(certClients) != 0 {
+			subject := r.TLS.VerifiedChains[0][0].Subject.CommonName
+			client := certClients[subject]
+
+			if client == nil {
+				return nil, ErrAuthUnknownCert
+			}
+
+			return client, nil
+		}
+	}
+
+	if len(authClients) == 0 {
+		return &AuthClient{Name: AUTH_ANONYMOUS, Scopes: []string{"*:*"}}, nil
+	}
+
+	ip := getRemoteIP(r)
+
+	if isAuthBlocked(ip) {
+		return nil, ErrAuthBlocked
+	}
+
+	token := getRequestToken(r)
+
+	if token == "" {
+		addAuthFailure(ip)
+		return nil, ErrAuthNoToken
+	}
+
+	var client *AuthClient
+
+	tokenHash := sha256.Sum256([]byte(token))
+
+	// Compare with all tokens to make timing independent of matched client
+	for _, c := range authClients {
+		if subtle.ConstantTimeCompare(tokenHash[:], c.tokenHash[:]) == 1 {
+			client = c
+		}
+	}
+
+	if client == nil {
+		addAuthFailure(ip)
+		return nil, ErrAuthInvalidToken
+	}
+
+	clearAuthFailures(ip)
+
+	return client, nil
+}
+
+// auditLog writes info about action triggered by client to log
+func auditLog(client *AuthClient, r *http.Request, action, target string, fields ...log.Field) {
+	lf := &log.Fields{}
+
+	lf.Add(
+		log.F{"audit", true},
+		log.F{"client", client.Name},
+		log.F{"remote-ip", getRemoteIP(r)},
+		log.F{"action", action},
+		log.F{"target", target},
+	)
+
+	lf.Add(fields...)
+
+	log.Info("Client %s triggered %s action", client.Name, action, lf)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// IsAllowed returns true if client has access to given action for given target.
+// Empty target matches any target.
+func (c *AuthClient) IsAllowed(target, action string) bool {
+	if c == nil {
+		return false
+	}
+
+	for _, scope := range c.Scopes {
+		scopeTarget, scopeAction, _ := strings.Cut(scope, ":")
+
+		if (target == "" || scopeTarget == "*" || scopeTarget == target) &&
+			(scopeAction == "*" || scopeAction == action) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getRequestToken returns access token from request
+func getRequestToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+
+	if auth != "" {
+		scheme, token, _ := strings.Cut(auth, " ")
+
+		if strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+
+		return ""
+	}
+
+	if r.URL.Query().Has("token") {
+		log.Warn(
+			"Access token passed using query string, use Authorization header instead",
+			log.F{"remote-ip", getRemoteIP(r)},
+		)
+
+		return r.URL.Query().Get("token")
+	}
+
+	return ""
+}
+
+// parseAuthScopes parses and validates list of scopes. Scope with target only
+// allows all actions for this target.
+func parseAuthScopes(list []string) ([]string, error) {
+	if len(list) == 0 {
+		return []string{"*:*"}, nil
+	}
+
+	var result []string
+
+	for _, scope := range list {
+		if !strings.Contains(scope, ":") {
+			scope += ":*"
+		}
+
+		err := validateAuthScope(scope)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, scope)
+	}
+
+	return result, nil
+}
+
+// validateAuthScope validates token scope
+func validateAuthScope(scope string) error {
+	target, action, _ := strings.Cut(scope, ":")
+
+	switch {
+	case !slices.Contains([]string{"*", TARGET_JIRA, TARGET_CONFLUENCE}, target):
+		return fmt.Errorf("Unknown target %q in scope %q", target, scope)
+	case !slices.Contains([]string{"*", ACTION_CREATE, ACTION_DOWNLOAD, ACTION_STATUS}, action):
+		return fmt.Errorf("Unknown action %q in scope %q", action, scope)
+	}
+
+	return nil
+}
+
+// getRemoteIP returns IP of remote client
+func getRemoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// isAuthBlocked returns true if client with given IP is blocked
+func isAuthBlocked(ip string) bool {
+	authMu.Lock()
+	defer authMu.Unlock()
+
+	a := authFailures[ip]
+
+	return a != nil && time.Now().Before(a.BlockedUntil)
+}
+
+// addAuthFailure registers failed authentication attempt
+func addAuthFailure(ip string) {
+	authMu.Lock()
+	defer authMu.Unlock()
+
+	now := time.Now()
+	a := authFailures[ip]
+
+	if a == nil || now.Sub(a.First) > AUTH_FAILURES_PERIOD {
+		a = &authAttempts{First: now}
+		authFailures[ip] = a
+	}
+
+	a.Count++
+
+	if a.Count >= AUTH_MAX_FAILURES {
+		a.BlockedUntil = now.Add(AUTH_BLOCK_PERIOD)
+		log.Warn(
+			"Client blocked due to too many failed authentication attempts",
+			log.F{"audit", true}, log.F{"remote-ip", ip},
+		)
+	}
+
+	for k, v := range authFailures {
+		if now.Sub(v.First) > AUTH_FAILURES_PERIOD && now.After(v.BlockedUntil) {
+			delete(authFailures, k)
+		}
+	}
+}
+
+// clearAuthFailures removes info about failed attempts for given IP
+func clearAuthFailures(ip string) {
+	authMu.Lock()
+	delete(authFailures, ip)
+	authMu.Unlock()
+}
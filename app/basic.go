@@ -8,96 +8,333 @@ package app
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/essentialkaos/ek/v13/events"
 	"github.com/essentialkaos/ek/v13/fmtc"
 	"github.com/essentialkaos/ek/v13/fmtutil"
+	"github.com/essentialkaos/ek/v13/fmtutil/table"
+	"github.com/essentialkaos/ek/v13/fsutil"
 	"github.com/essentialkaos/ek/v13/log"
 	"github.com/essentialkaos/ek/v13/options"
 	"github.com/essentialkaos/ek/v13/path"
 	"github.com/essentialkaos/ek/v13/spinner"
 	"github.com/essentialkaos/ek/v13/terminal"
+	"github.com/essentialkaos/ek/v13/timeutil"
+	"github.com/essentialkaos/ek/v13/uuid"
 
 	knfu "github.com/essentialkaos/ek/v13/knf/united"
 
 	"github.com/essentialkaos/atlassian-cloud-backuper/backuper"
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor"
+	"github.com/essentialkaos/atlassian-cloud-backuper/history"
+	"github.com/essentialkaos/atlassian-cloud-backuper/jobs"
+	"github.com/essentialkaos/atlassian-cloud-backuper/notifier"
+	"github.com/essentialkaos/atlassian-cloud-backuper/runlog"
 	"github.com/essentialkaos/atlassian-cloud-backuper/uploader"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+var (
+	// downloadSlots limits number of concurrent backup downloads
+	downloadSlots chan struct{}
+
+	// uploadSlots limits number of concurrent backup uploads
+	uploadSlots chan struct{}
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
 // startApp starts app in basic mode
 func startApp(args options.Arguments) error {
-	var dispatcher *events.Dispatcher
-
-	target := args.Get(0).String()
+	targets, err := getTargets(args)
 
-	if options.GetB(OPT_INTERACTIVE) {
-		dispatcher = events.NewDispatcher()
-		addEventsHandlers(dispatcher)
+	if err != nil {
+		return err
 	}
 
-	if knfu.GetS(STORAGE_ENCRYPTION_KEY) != "" && options.GetB(OPT_INTERACTIVE) {
-		fmtc.NewLine()
-		terminal.Warn("▲ Backup will be encrypted while uploading. You will not be able to use the")
-		terminal.Warn("  backup if you lose the encryption key. Keep it in a safe place.")
+	if options.GetB(OPT_INTERACTIVE) {
+		switch getEncryptionType() {
+		case ENCRYPTION_KATANA:
+			fmtc.NewLine()
+			terminal.Warn("▲ Backup will be encrypted while uploading. You will not be able to use the")
+			terminal.Warn("  backup if you lose the encryption key. Keep it in a safe place.")
+		case ENCRYPTION_AGE:
+			fmtc.NewLine()
+			terminal.Warn("▲ Backup will be encrypted with age while uploading. You will not be able to")
+			terminal.Warn("  use the backup if you lose the private identity. Keep it in a safe place.")
+		case ENCRYPTION_ENVELOPE:
+			fmtc.NewLine()
+			terminal.Warn("▲ Backup will be encrypted with data key wrapped by KMS. You will not be able")
+			terminal.Warn("  to use the backup if you lose access to the KMS key.")
+		}
 	}
 
 	defer temp.Clean()
 
+	downloadSlots = make(chan struct{}, knfu.GetI(CONCURRENCY_DOWNLOADS, 1))
+	uploadSlots = make(chan struct{}, knfu.GetI(CONCURRENCY_UPLOADS, 1))
+
 	fmtc.If(options.GetB(OPT_INTERACTIVE)).NewLine()
 
-	bkpr, err := getBackuper(target)
+	if len(targets) == 1 {
+		dispatcher := events.NewDispatcher()
+
+		if options.GetB(OPT_INTERACTIVE) {
+			addEventsHandlers(dispatcher)
+		}
+
+		_, err = backupTarget(targets[0], dispatcher)
+
+		return err
+	}
+
+	return backupTargets(targets)
+}
+
+// backupTargets runs backups for all given targets at the same time and prints
+// combined summary
+func backupTargets(targets []string) error {
+	var wg sync.WaitGroup
+
+	reports := make([]*notifier.Notification, len(targets))
+
+	log.Info("Starting backup of %s", strings.Join(targets, ", "))
+
+	for i, target := range targets {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			dispatcher := events.NewDispatcher()
+
+			if options.GetB(OPT_INTERACTIVE) {
+				addTargetEventsHandlers(dispatcher, target)
+			}
+
+			reports[i], _ = backupTarget(target, dispatcher)
+		}()
+	}
+
+	wg.Wait()
+
+	var failed []string
+
+	for _, report := range reports {
+		if report.Error != "" {
+			failed = append(failed, report.Target)
+			log.Error("Backup of %s failed: %s", report.Target, report.Error)
+		} else {
+			log.Info(
+				"Backup of %s successfully finished", report.Target,
+				log.F{"file", report.File}, log.F{"size", report.Size},
+			)
+		}
+	}
+
+	if options.GetB(OPT_INTERACTIVE) {
+		printBackupSummary(reports)
+	}
+
+	if len(failed) != 0 {
+		return fmt.Errorf("Backup failed for %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// backupTarget runs backup for given target and sends notifications about it
+func backupTarget(target string, dispatcher *events.Dispatcher) (*notifier.Notification, error) {
+	addMetricsHandlers(dispatcher, target)
+
+	start := time.Now()
+	report := &notifier.Notification{
+		Target:    target,
+		Account:   knfu.GetS(ACCESS_ACCOUNT),
+		Job:       uuid.UUID7().String(),
+		JobType:   jobs.TYPE_BACKUP,
+		Durations: map[string]float64{},
+	}
+
+	notifyBackupEvent(notifier.EVENT_START, report)
+
+	rl := runlog.New(RECENT_LOG_LINES)
+	err := runBackup(target, dispatcher, rl, report)
+
+	report.Duration = time.Since(start).Seconds()
+
+	if err != nil {
+		report.Error = err.Error()
+		report.Logs = rl.Records()
+	}
+
+	recordRun(history.TRIGGER_CLI, report, start)
+
+	if err != nil {
+		notifyBackupEvent(notifier.EVENT_FAILURE, report)
+	} else {
+		notifyBackupEvent(notifier.EVENT_SUCCESS, report)
+	}
+
+	observeResult(target, err)
+	exportRunMetrics(target)
+
+	return report, err
+}
+
+// runBackup creates backup for given target and uploads it to storage and
+// saves info about backup to given report
+func runBackup(target string, dispatcher *events.Dispatcher, rl *runlog.Log, report *notifier.Notification) error {
+	tmpEnc, err := getTempEncryptor()
 
 	if err != nil {
-		sendUpdownPulse(false, err.Error())
 		return fmt.Errorf("Can't start backuping process: %w", err)
 	}
 
-	updr, err := getUploader(target)
+	bkpr, err := getBackuper(target, tmpEnc)
+
+	if err != nil {
+		return fmt.Errorf("Can't start backuping process: %w", err)
+	}
+
+	var tmpDec encryptor.Decryptor
+
+	if tmpEnc != nil {
+		tmpDec = tmpEnc
+	}
+
+	updr, err := getUploader(target, tmpDec)
 
 	if err != nil {
 		return fmt.Errorf("Can't start backuping process: %w", err)
 	}
 
 	bkpr.SetDispatcher(dispatcher)
+	bkpr.SetLogger(rl)
 	updr.SetDispatcher(dispatcher)
+	updr.SetLogger(rl)
 
 	outputFileName := getOutputFileName(target)
 	tmpDir, err := temp.MkDir()
 
 	if err != nil {
 		spinner.Done(false)
-		sendUpdownPulse(false, err.Error())
 		return fmt.Errorf("Can't create temporary directory: %w", err)
 	}
 
 	tmpFile := path.Join(tmpDir, outputFileName)
 
+	var downloadStart time.Time
+
+	start := time.Now()
+
+	dispatcher.AddHandler(backuper.EVENT_BACKUP_STARTED, func(payload any) {
+		report.TaskID, _ = payload.(string)
+	})
+
+	// Backuper waits for handlers of this event, so backup file will not be
+	// downloaded until download slot is acquired
+	dispatcher.AddHandler(backuper.EVENT_BACKUP_SAVING, func(payload any) {
+		downloadSlots <- struct{}{}
+		downloadStart = time.Now()
+		observePhase(target, jobs.PHASE_CREATING, start)
+	})
+
 	err = bkpr.Backup(tmpFile, options.GetB(OPT_FORCE))
 
+	if !downloadStart.IsZero() {
+		<-downloadSlots
+	}
+
+	// Creation phase of downloaded backup is recorded when downloading is
+	// started, so metrics contain the same phases as report
+	if downloadStart.IsZero() {
+		observePhase(target, jobs.PHASE_CREATING, start)
+		report.Durations[jobs.PHASE_CREATING] = time.Since(start).Seconds()
+	} else {
+		observePhase(target, jobs.PHASE_DOWNLOADING, downloadStart)
+		report.Durations[jobs.PHASE_CREATING] = downloadStart.Sub(start).Seconds()
+		report.Durations[jobs.PHASE_DOWNLOADING] = time.Since(downloadStart).Seconds()
+	}
+
 	if err != nil {
 		spinner.Done(false)
-		sendUpdownPulse(false, err.Error())
 		return fmt.Errorf("Error while backuping process: %w", err)
 	}
 
-	log.Info("Backup process successfully finished!")
+	rl.Info("Backup process successfully finished!")
 
+	// Size of temporary file differs from size of backup if temporary file is
+	// encrypted, so size of decrypted data is used instead. Checksum is used
+	// only for reports, so there is no reason to read whole backup file if it
+	// isn't encrypted and there are no notifiers.
+	size := fsutil.GetSize(tmpFile)
+
+	if tmpDec != nil || notifications.Size() > 0 {
+		var checksum string
+
+		checksum, size, err = getBackupInfo(tmpFile, tmpDec)
+
+		if err != nil {
+			rl.Error("Can't calculate backup checksum: %v", err)
+		} else {
+			report.Checksum = checksum
+		}
+	}
+
+	uploadSlots <- struct{}{}
+
+	start = time.Now()
 	err = updr.Upload(tmpFile, outputFileName)
 
+	<-uploadSlots
+
+	observePhase(target, jobs.PHASE_UPLOADING, start)
+	report.Durations[jobs.PHASE_UPLOADING] = time.Since(start).Seconds()
+
 	if err != nil {
 		spinner.Done(false)
-		sendUpdownPulse(false, err.Error())
 		return fmt.Errorf("Error while uploading process: %w", err)
 	}
 
-	sendUpdownPulse(true, "ok")
+	observeUpload(target, size, start)
+
+	report.File = outputFileName
+	report.Size = size
+	report.Location = getStorageLocation(target, outputFileName)
 
 	return nil
 }
 
+// getBackupInfo returns SHA-256 checksum and size of backup data
+func getBackupInfo(file string, dec encryptor.Decryptor) (string, int64, error) {
+	r, err := uploader.OpenSource(file, dec)
+
+	if err != nil {
+		return "", 0, fmt.Errorf("Can't open backup file: %w", err)
+	}
+
+	defer r.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, r)
+
+	if err != nil {
+		return "", 0, fmt.Errorf("Can't read backup data: %w", err)
+	}
+
+	return "sha256:" + hex.EncodeToString(hasher.Sum(nil)), size, nil
+}
+
 // addEventsHandlers registers events handlers
 func addEventsHandlers(dispatcher *events.Dispatcher) {
 	dispatcher.AddHandler(backuper.EVENT_BACKUP_STARTED, func(payload any) {
@@ -138,3 +375,84 @@ func addEventsHandlers(dispatcher *events.Dispatcher) {
 		fmtc.NewLine()
 	})
 }
+
+// addTargetEventsHandlers registers events handlers for printing progress of
+// backup when several targets are processed at the same time
+func addTargetEventsHandlers(dispatcher *events.Dispatcher, target string) {
+	dispatcher.AddHandler(backuper.EVENT_BACKUP_STARTED, func(payload any) {
+		fmtc.Printfn("{s}[%-10s]{!} Backup creation started", target)
+	})
+
+	dispatcher.AddHandler(backuper.EVENT_BACKUP_SAVING, func(payload any) {
+		fmtc.Printfn("{s}[%-10s]{!} Fetching backup file", target)
+	})
+
+	dispatcher.AddHandler(backuper.EVENT_BACKUP_DONE, func(payload any) {
+		fmtc.Printfn("{s}[%-10s]{!} Backup file successfully fetched", target)
+	})
+
+	dispatcher.AddHandler(uploader.EVENT_UPLOAD_STARTED, func(payload any) {
+		fmtc.Printfn("{s}[%-10s]{!} Uploading backup file to %s storage", target, payload)
+	})
+
+	dispatcher.AddHandler(uploader.EVENT_UPLOAD_DONE, func(payload any) {
+		fmtc.Printfn("{s}[%-10s]{!} Backup file successfully uploaded", target)
+	})
+}
+
+// printBackupSummary prints summary for backups of several targets
+func printBackupSummary(reports []*notifier.Notification) {
+	fmtc.NewLine()
+
+	t := table.NewTable("TARGET", "RESULT", "DURATION", "SIZE", "FILE")
+	t.SetAlignments(table.ALIGN_LEFT, table.ALIGN_LEFT, table.ALIGN_RIGHT,
+		table.ALIGN_RIGHT, table.ALIGN_LEFT)
+
+	for _, r := range reports {
+		dur := timeutil.PrettyDurationSimple(time.Duration(r.Duration * float64(time.Second)))
+
+		if r.Error != "" {
+			t.Add(r.Target, "{r}failure{!}", dur, "—", "—")
+		} else {
+			t.Add(r.Target, "{g}success{!}", dur, fmtutil.PrettySize(r.Size), r.File)
+		}
+	}
+
+	t.Render()
+
+	for _, r := range reports {
+		if r.Error != "" {
+			terminal.Error("%s: %s", r.Target, r.Error)
+		}
+	}
+
+	fmtc.NewLine()
+}
+
+// getTargets returns list of targets from command arguments
+func getTargets(args options.Arguments) ([]string, error) {
+	var targets []string
+
+	for _, arg := range args {
+		var argTargets []string
+
+		switch strings.ToLower(arg.String()) {
+		case TARGET_ALL:
+			argTargets = []string{TARGET_JIRA, TARGET_CONFLUENCE}
+		case TARGET_JIRA:
+			argTargets = []string{TARGET_JIRA}
+		case TARGET_CONFLUENCE:
+			argTargets = []string{TARGET_CONFLUENCE}
+		default:
+			return nil, fmt.Errorf("Unknown target %q", arg.String())
+		}
+
+		for _, target := range argTargets {
+			if !slices.Contains(targets, target) {
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,224 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/req"
+
+	knfu "github.com/essentialkaos/ek/v13/knf/united"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/jobs"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	OUTCOME_SUCCESS = "success"
+	OUTCOME_FAILURE = "failure"
+)
+
+const (
+	// CALLBACK_MAX_ATTEMPTS is max number of callback delivery attempts
+	CALLBACK_MAX_ATTEMPTS = 10
+
+	// CALLBACK_MIN_DELAY is delay before the first retry
+	CALLBACK_MIN_DELAY = 5 * time.Second
+
+	// CALLBACK_MAX_DELAY is max delay between retries
+	CALLBACK_MAX_DELAY = 15 * time.Minute
+
+	// CALLBACK_TIMEOUT is callback request timeout
+	CALLBACK_TIMEOUT = 30 * time.Second
+)
+
+const (
+	CALLBACK_HEADER_SIGNATURE = "X-Backuper-Signature"
+	CALLBACK_HEADER_TIMESTAMP = "X-Backuper-Timestamp"
+	CALLBACK_HEADER_JOB       = "X-Backuper-Job"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// CallbackPayload contains info about finished job sent to callback URL
+type CallbackPayload struct {
+	JobID    string    `json:"job_id"`
+	JobType  string    `json:"job_type"`
+	Target   string    `json:"target"`
+	Outcome  string    `json:"outcome"`
+	Error    string    `json:"error,omitempty"`
+	File     string    `json:"file,omitempty"`
+	Size     int64     `json:"size,omitempty"`
+	Checksum string    `json:"checksum,omitempty"`
+	Location string    `json:"location,omitempty"`
+	Finished time.Time `json:"finished"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getCallbackURL returns callback URL from request query or default callback URL
+func getCallbackURL(query url.Values) (string, error) {
+	callbackURL := query.Get("callback")
+
+	if callbackURL == "" {
+		return knfu.GetS(SERVER_CALLBACK_URL), nil
+	}
+
+	if knfu.GetS(SERVER_CALLBACK_SECRET) == "" {
+		return "", fmt.Errorf("Callbacks are disabled: callback secret is not configured")
+	}
+
+	u, err := url.Parse(callbackURL)
+
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("Invalid callback URL %q", callbackURL)
+	}
+
+	return callbackURL, nil
+}
+
+// addJobCallback saves callback URL with job state and sends info about job
+// result to it when job is finished
+func addJobCallback(job *jobs.Job, callbackURL string) {
+	if callbackURL == "" {
+		return
+	}
+
+	job.SetCallback(callbackURL)
+
+	go func() {
+		job.Wait()
+
+		if job.IsInterrupted() {
+			return
+		}
+
+		err := sendCallback(callbackURL, getCallbackPayload(job.Info()))
+
+		if err != nil {
+			log.Error(
+				"Can't deliver job callback: %v", err,
+				log.F{"job-id", job.ID()}, log.F{"callback-url", callbackURL},
+			)
+
+			notifyWarning(job.Info().Target, fmt.Sprintf("Can't deliver callback for job %s: %v", job.ID(), err))
+		}
+	}()
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getCallbackPayload creates callback payload from job info
+func getCallbackPayload(info jobs.Info) *CallbackPayload {
+	payload := &CallbackPayload{
+		JobID:    info.ID,
+		JobType:  info.Type,
+		Target:   info.Target,
+		Outcome:  OUTCOME_SUCCESS,
+		Error:    info.Error,
+		Finished: info.Finished,
+	}
+
+	if info.Phase == jobs.PHASE_FAILED {
+		payload.Outcome = OUTCOME_FAILURE
+	}
+
+	if info.Result != nil {
+		payload.File = info.Result.File
+		payload.Size = info.Result.Size
+		payload.Checksum = info.Result.Checksum
+		payload.Location = info.Result.Location
+	}
+
+	return payload
+}
+
+// sendCallback sends payload to callback URL and retries until it is acknowledged
+// with 2xx status code
+func sendCallback(callbackURL string, payload *CallbackPayload) error {
+	data, err := json.Marshal(payload)
+
+	if err != nil {
+		return fmt.Errorf("Can't encode callback payload: %w", err)
+	}
+
+	delay := CALLBACK_MIN_DELAY
+
+	for attempt := 1; ; attempt++ {
+		err = deliverCallback(callbackURL, payload.JobID, data)
+
+		if err == nil {
+			log.Info(
+				"Job callback delivered",
+				log.F{"job-id", payload.JobID}, log.F{"attempt", attempt},
+			)
+
+			return nil
+		}
+
+		if attempt >= CALLBACK_MAX_ATTEMPTS {
+			return fmt.Errorf("Giving up after %d attempts: %w", attempt, err)
+		}
+
+		log.Warn(
+			"Can't deliver job callback, will retry in %v: %v", delay, err,
+			log.F{"job-id", payload.JobID}, log.F{"attempt", attempt},
+		)
+
+		time.Sleep(delay)
+
+		delay = min(delay*2, CALLBACK_MAX_DELAY)
+	}
+}
+
+// deliverCallback sends signed payload to callback URL
+func deliverCallback(callbackURL, jobID string, data []byte) error {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	resp, err := req.Request{
+		URL:         callbackURL,
+		ContentType: req.CONTENT_TYPE_JSON,
+		Body:        data,
+		Timeout:     CALLBACK_TIMEOUT,
+		AutoDiscard: true,
+		Headers: req.Headers{
+			CALLBACK_HEADER_JOB:       jobID,
+			CALLBACK_HEADER_TIMESTAMP: timestamp,
+			CALLBACK_HEADER_SIGNATURE: "sha256=" + signCallback(timestamp, data),
+		},
+	}.Post()
+
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Callback URL returned non-ok status code (%d)", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// signCallback returns HMAC-SHA256 signature of timestamp and payload
+func signCallback(timestamp string, data []byte) string {
+	mac := hmac.New(sha256.New, []byte(knfu.GetS(SERVER_CALLBACK_SECRET)))
+
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(data)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,163 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/essentialkaos/ek/v13/fmtc"
+	"github.com/essentialkaos/ek/v13/fmtutil"
+	"github.com/essentialkaos/ek/v13/fmtutil/table"
+	"github.com/essentialkaos/ek/v13/options"
+	"github.com/essentialkaos/ek/v13/strutil"
+	"github.com/essentialkaos/ek/v13/terminal"
+	"github.com/essentialkaos/ek/v13/timeutil"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/uploader"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// printCatalog prints info about backups in storage
+func printCatalog(args options.Arguments) int {
+	targets, err := getCatalogTargets(args)
+
+	if err != nil {
+		terminal.Error(err)
+		return 1
+	}
+
+	catalogs := map[string]*uploader.Catalog{}
+
+	for _, target := range targets {
+		updr, err := getUploaderWithEncryptor(target, nil, nil)
+
+		if err != nil {
+			terminal.Error(err)
+			return 1
+		}
+
+		catalogs[target], err = uploader.ReadCatalog(updr)
+
+		if err != nil {
+			terminal.Error("Can't read %s backups catalog: %v", target, err)
+			return 1
+		}
+	}
+
+	if options.GetB(OPT_JSON) {
+		return printCatalogJSON(catalogs)
+	}
+
+	for _, target := range targets {
+		printTargetCatalog(target, catalogs[target])
+	}
+
+	return 0
+}
+
+// reindexCatalog rebuilds backups catalog using storage listing
+func reindexCatalog(args options.Arguments) int {
+	targets, err := getCatalogTargets(args)
+
+	if err != nil {
+		terminal.Error(err)
+		return 1
+	}
+
+	for _, target := range targets {
+		updr, err := getUploaderWithEncryptor(target, nil, nil)
+
+		if err != nil {
+			terminal.Error(err)
+			return 1
+		}
+
+		catalog, err := uploader.RebuildCatalog(updr)
+
+		if err != nil {
+			terminal.Error("Can't rebuild %s backups catalog: %v", target, err)
+			return 1
+		}
+
+		fmtc.Printfn(
+			"{g}Catalog for {g*}%s{!*} successfully rebuilt {s}(backups: %d | total size: %s){!}",
+			target, len(catalog.Backups), fmtutil.PrettySize(catalog.Size()),
+		)
+	}
+
+	return 0
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// printTargetCatalog prints catalog for given target as a table
+func printTargetCatalog(target string, catalog *uploader.Catalog) {
+	if len(catalog.Backups) == 0 {
+		terminal.Warn("No backups found for %s", target)
+		return
+	}
+
+	fmtc.NewLine()
+	fmtc.Printfn(
+		"{*}%s{!} {s}(backups: %d | total size: %s){!}",
+		target, len(catalog.Backups), fmtutil.PrettySize(catalog.Size()),
+	)
+
+	t := table.NewTable("CREATED", "FILE", "SIZE", "ENCRYPTION", "KEY", "CHECKSUM", "VERSION")
+	t.SetAlignments(table.ALIGN_LEFT, table.ALIGN_LEFT, table.ALIGN_RIGHT, table.ALIGN_LEFT,
+		table.ALIGN_LEFT, table.ALIGN_LEFT, table.ALIGN_LEFT)
+
+	// Newest backups are shown first
+	for _, r := range slices.Backward(catalog.Backups) {
+		checksum := strings.TrimPrefix(r.Checksum, "sha256:")
+
+		t.Add(
+			timeutil.Format(r.Created.Local(), "%Y/%m/%d %H:%M"),
+			r.File, fmtutil.PrettySize(r.Size),
+			strutil.Q(r.Encryption, "—"),
+			strutil.Q(r.KeyID, "—"),
+			strutil.Q(strutil.Head(checksum, 12), "—"),
+			strutil.Q(r.Version, "—"),
+		)
+	}
+
+	t.Render()
+}
+
+// printCatalogJSON prints catalogs as JSON
+func printCatalogJSON(catalogs map[string]*uploader.Catalog) int {
+	data, err := json.MarshalIndent(catalogs, "", "  ")
+
+	if err != nil {
+		terminal.Error("Can't encode catalog: %v", err)
+		return 1
+	}
+
+	fmt.Fprintln(os.Stdout, string(data))
+
+	return 0
+}
+
+// getCatalogTargets returns list of targets for catalog commands
+func getCatalogTargets(args options.Arguments) ([]string, error) {
+	target := args.Get(1).String()
+
+	switch target {
+	case "":
+		return []string{TARGET_JIRA, TARGET_CONFLUENCE}, nil
+	case TARGET_JIRA, TARGET_CONFLUENCE:
+		return []string{target}, nil
+	}
+
+	return nil, fmt.Errorf("Unknown target %q", target)
+}
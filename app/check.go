@@ -0,0 +1,160 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/essentialkaos/ek/v13/fmtc"
+	"github.com/essentialkaos/ek/v13/terminal"
+	"github.com/essentialkaos/ek/v13/uuid"
+
+	knfu "github.com/essentialkaos/ek/v13/knf/united"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// runPreflightCheck checks configuration, access to Atlassian API and storage
+// and returns exit code
+func runPreflightCheck() int {
+	setupReq()
+
+	ok := checkConfiguration()
+	ok = checkAtlassianAccess() && ok
+	ok = checkStorageAccess() && ok
+
+	fmtc.NewLine()
+
+	if !ok {
+		terminal.Error("Some checks failed")
+		return 1
+	}
+
+	fmtc.Printfn("{g}All checks successfully passed{!}")
+
+	return 0
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// checkConfiguration validates configuration and prints all found errors
+func checkConfiguration() bool {
+	fmtc.Printfn("\n{*}Configuration{!}\n")
+
+	errs := knfu.Validate(getConfigValidators())
+
+	if errs.IsEmpty() {
+		return printCheckResult("Configuration is valid", nil)
+	}
+
+	for _, err := range errs {
+		printCheckResult("Configuration error", err)
+	}
+
+	return false
+}
+
+// checkAtlassianAccess checks credentials and user permissions for all targets
+func checkAtlassianAccess() bool {
+	fmtc.Printfn("\n{*}Atlassian{!}\n")
+
+	ok := true
+
+	for _, target := range []string{TARGET_JIRA, TARGET_CONFLUENCE} {
+		ok = printCheckResult(
+			fmt.Sprintf("Access to %s with administrator permissions", target),
+			checkTargetAccess(target),
+		) && ok
+	}
+
+	return ok
+}
+
+// checkStorageAccess checks if backups can be written to storage for all targets
+func checkStorageAccess() bool {
+	fmtc.Printfn("\n{*}Storage{!}\n")
+
+	ok := true
+
+	for _, target := range []string{TARGET_JIRA, TARGET_CONFLUENCE} {
+		ok = printCheckResult(
+			fmt.Sprintf(
+				"Writing %s backups to %s storage",
+				target, strings.ToUpper(knfu.GetS(STORAGE_TYPE)),
+			),
+			checkTargetStorage(target),
+		) && ok
+	}
+
+	return ok
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// checkTargetAccess checks credentials and user permissions for given target
+func checkTargetAccess(target string) error {
+	bkpr, err := getBackuper(target, nil)
+
+	if err != nil {
+		return err
+	}
+
+	err = bkpr.CheckAccess()
+
+	if err != nil {
+		return err
+	}
+
+	return bkpr.CheckPermissions()
+}
+
+// checkTargetStorage writes and deletes test object in storage for given target
+func checkTargetStorage(target string) error {
+	updr, err := getUploaderWithEncryptor(target, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	err = updr.Check()
+
+	if err != nil {
+		return err
+	}
+
+	// Name starts with dot, so object will be ignored while rebuilding catalog
+	// if it wasn't removed
+	testFile := ".check-" + uuid.UUID7().String()
+
+	err = updr.WriteFile(testFile, []byte("check"))
+
+	if err != nil {
+		return fmt.Errorf("Can't write test object: %w", err)
+	}
+
+	err = updr.DeleteFile(testFile)
+
+	if err != nil {
+		return fmt.Errorf("Can't delete test object %s: %w", testFile, err)
+	}
+
+	return nil
+}
+
+// printCheckResult prints check result and returns true if check is passed
+func printCheckResult(name string, err error) bool {
+	if err != nil {
+		fmtc.Printfn("  {r}✖ {!}%s: {r}%v{!}", name, err)
+		return false
+	}
+
+	fmtc.Printfn("  {g}✔ {!}%s", name)
+
+	return true
+}
@@ -8,6 +8,7 @@ package app
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
+	"crypto/rand"
 	"encoding/base64"
 	"fmt"
 	"os"
@@ -17,17 +18,20 @@ import (
 	"github.com/essentialkaos/ek/v13/fsutil"
 	"github.com/essentialkaos/ek/v13/log"
 	"github.com/essentialkaos/ek/v13/path"
-	"github.com/essentialkaos/ek/v13/strutil"
 	"github.com/essentialkaos/ek/v13/timeutil"
 
 	knfu "github.com/essentialkaos/ek/v13/knf/united"
 
-	"github.com/essentialkaos/katana"
-	"github.com/essentialkaos/updown"
-
 	"github.com/essentialkaos/atlassian-cloud-backuper/backuper"
 	"github.com/essentialkaos/atlassian-cloud-backuper/backuper/confluence"
 	"github.com/essentialkaos/atlassian-cloud-backuper/backuper/jira"
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor"
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor/age"
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor/envelope"
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor/katana"
+	"github.com/essentialkaos/atlassian-cloud-backuper/kms"
+	"github.com/essentialkaos/atlassian-cloud-backuper/kms/awskms"
+	"github.com/essentialkaos/atlassian-cloud-backuper/kms/vault"
 	"github.com/essentialkaos/atlassian-cloud-backuper/uploader"
 	"github.com/essentialkaos/atlassian-cloud-backuper/uploader/fs"
 	"github.com/essentialkaos/atlassian-cloud-backuper/uploader/s3"
@@ -36,8 +40,9 @@ import (
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
-// getBackuper returns backuper instances
-func getBackuper(target string) (backuper.Backuper, error) {
+// getBackuper returns backuper instances. Given encryptor is used for encrypting
+// backup data saved to local disk.
+func getBackuper(target string, enc encryptor.Encryptor) (backuper.Backuper, error) {
 	var err error
 	var bkpr backuper.Backuper
 
@@ -47,6 +52,8 @@ func getBackuper(target string) (backuper.Backuper, error) {
 		return nil, err
 	}
 
+	bkpConfig.Encryptor = enc
+
 	switch target {
 	case TARGET_JIRA:
 		bkpr, err = jira.NewBackuper(bkpConfig)
@@ -65,7 +72,7 @@ func getOutputFileName(target string) string {
 	case TARGET_JIRA:
 		template = knfu.GetS(JIRA_OUTPUT_FILE, `jira-backup-%Y-%m-%d`) + ".zip"
 	case TARGET_CONFLUENCE:
-		template = knfu.GetS(JIRA_OUTPUT_FILE, `confluence-backup-%Y-%m-%d`) + ".zip"
+		template = knfu.GetS(CONFLUENCE_OUTPUT_FILE, `confluence-backup-%Y-%m-%d`) + ".zip"
 	}
 
 	return timeutil.Format(time.Now(), template)
@@ -96,20 +103,31 @@ func getBackuperConfig(target string) (*backuper.Config, error) {
 	return nil, fmt.Errorf("Unknown target %q", target)
 }
 
-// getUploader returns uploader instance
-func getUploader(target string) (uploader.Uploader, error) {
-	var secret *katana.Secret
+// getUploader returns uploader instance. Decryptor is used for decrypting
+// temporary files and can be nil if temporary data is not encrypted.
+func getUploader(target string, dec encryptor.Decryptor) (uploader.Uploader, error) {
+	enc, err := getEncryptor()
 
-	if knfu.GetS(STORAGE_ENCRYPTION_KEY) != "" {
-		secret = katana.NewSecret(knfu.GetS(STORAGE_ENCRYPTION_KEY))
+	if err != nil {
+		return nil, err
 	}
 
+	return getUploaderWithEncryptor(target, enc, dec)
+}
+
+// getUploaderWithEncryptor returns uploader instance which uses given encryptor
+// and decryptor. Encryptor can be nil if uploader is used only for accessing
+// catalog.
+func getUploaderWithEncryptor(target string, enc encryptor.Encryptor, dec encryptor.Decryptor) (uploader.Uploader, error) {
 	switch strings.ToLower(knfu.GetS(STORAGE_TYPE)) {
 	case STORAGE_FS:
 		return fs.NewUploader(&fs.Config{
-			Secret: secret,
-			Path:   path.Join(knfu.GetS(STORAGE_FS_PATH), target),
-			Mode:   knfu.GetM(STORAGE_FS_MODE, 0600),
+			Encryptor: enc,
+			Decryptor: dec,
+			Path:      path.Join(knfu.GetS(STORAGE_FS_PATH), target),
+			StateDir:  getUploadStateDir(),
+			Version:   VER,
+			Mode:      knfu.GetM(STORAGE_FS_MODE, 0600),
 		})
 
 	case STORAGE_SFTP:
@@ -120,17 +138,22 @@ func getUploader(target string) (uploader.Uploader, error) {
 		}
 
 		return sftp.NewUploader(&sftp.Config{
-			Secret: secret,
-			Host:   knfu.GetS(STORAGE_SFTP_HOST),
-			User:   knfu.GetS(STORAGE_SFTP_USER),
-			Key:    keyData,
-			Path:   path.Join(knfu.GetS(STORAGE_SFTP_PATH), target),
-			Mode:   knfu.GetM(STORAGE_SFTP_MODE, 0600),
+			Encryptor: enc,
+			Decryptor: dec,
+			Host:      knfu.GetS(STORAGE_SFTP_HOST),
+			User:      knfu.GetS(STORAGE_SFTP_USER),
+			Key:       keyData,
+			Path:      path.Join(knfu.GetS(STORAGE_SFTP_PATH), target),
+			StateDir:  getUploadStateDir(),
+			Version:   VER,
+			Mode:      knfu.GetM(STORAGE_SFTP_MODE, 0600),
 		})
 
 	case STORAGE_S3:
 		return s3.NewUploader(&s3.Config{
-			Secret:      secret,
+			Encryptor: enc,
+			Decryptor: dec,
+
 			Host:        knfu.GetS(STORAGE_S3_HOST),
 			Region:      knfu.GetS(STORAGE_S3_REGION),
 			AccessKeyID: knfu.GetS(STORAGE_S3_ACCESS_KEY),
@@ -138,12 +161,157 @@ func getUploader(target string) (uploader.Uploader, error) {
 			Bucket:      knfu.GetS(STORAGE_S3_BUCKET),
 			Path:        path.Join(knfu.GetS(STORAGE_S3_PATH), target),
 			PartSize:    knfu.GetSZ(STORAGE_S3_PART_SIZE, 5*1024*1024),
+			Version:     VER,
+
+			StateDir:     getUploadStateDir(),
+			StaleTimeout: knfu.GetTD(STORAGE_S3_STALE_TIMEOUT, 24*time.Hour),
 		})
 	}
 
 	return nil, fmt.Errorf("Unknown storage type %q", knfu.GetS(STORAGE_TYPE))
 }
 
+// getStorageLocation returns URL of file with given name in storage
+func getStorageLocation(target, fileName string) string {
+	switch strings.ToLower(knfu.GetS(STORAGE_TYPE)) {
+	case STORAGE_FS:
+		return "file://" + path.Join(knfu.GetS(STORAGE_FS_PATH), target, fileName)
+
+	case STORAGE_SFTP:
+		return "sftp://" + knfu.GetS(STORAGE_SFTP_USER) + "@" + knfu.GetS(STORAGE_SFTP_HOST) +
+			path.Join("/", knfu.GetS(STORAGE_SFTP_PATH), target, fileName)
+
+	case STORAGE_S3:
+		return "s3://" + knfu.GetS(STORAGE_S3_BUCKET) +
+			path.Join("/", knfu.GetS(STORAGE_S3_PATH), target, fileName)
+	}
+
+	return ""
+}
+
+// getEncryptor returns encryptor instance if data encryption is enabled
+func getEncryptor() (encryptor.Encryptor, error) {
+	switch getEncryptionType() {
+	case ENCRYPTION_AGE:
+		enc, err := age.NewEncryptor(knfu.GetL(STORAGE_ENCRYPTION_RECIPIENTS))
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't create age encryptor: %w", err)
+		}
+
+		return enc, nil
+
+	case ENCRYPTION_KATANA:
+		keyID := knfu.GetS(STORAGE_ENCRYPTION_KEY_ID)
+		key := knfu.GetS(STORAGE_ENCRYPTION_KEY)
+
+		if keyID != "" {
+			key = knfu.GetS(ENCRYPTION_KEYS + ":" + keyID)
+		}
+
+		enc, err := katana.NewEncryptor(keyID, key)
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't create katana encryptor: %w", err)
+		}
+
+		return enc, nil
+
+	case ENCRYPTION_ENVELOPE:
+		provider, err := getKMSProvider()
+
+		if err != nil {
+			return nil, err
+		}
+
+		enc, err := envelope.NewEncryptor(provider)
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't create envelope encryptor: %w", err)
+		}
+
+		return enc, nil
+	}
+
+	return nil, nil
+}
+
+// getTempEncryptor returns encryptor for temporary data if temporary data
+// encryption is enabled
+func getTempEncryptor() (*katana.KatanaEncryptor, error) {
+	if !knfu.GetB(TEMP_ENCRYPT, true) {
+		return nil, nil
+	}
+
+	key := knfu.GetS(TEMP_ENCRYPTION_KEY)
+
+	if key == "" {
+		data := make([]byte, 32)
+		_, err := rand.Read(data)
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't generate ephemeral key: %w", err)
+		}
+
+		key = base64.StdEncoding.EncodeToString(data)
+	}
+
+	return katana.NewEncryptor("", key)
+}
+
+// getKMSProvider returns KMS provider instance for envelope encryption
+func getKMSProvider() (kms.Provider, error) {
+	switch strings.ToLower(knfu.GetS(KMS_PROVIDER)) {
+	case KMS_VAULT:
+		return vault.NewProvider(&vault.Config{
+			Address: knfu.GetS(KMS_VAULT_ADDRESS),
+			Token:   knfu.GetS(KMS_VAULT_TOKEN),
+			Mount:   knfu.GetS(KMS_VAULT_MOUNT, "transit"),
+			Key:     knfu.GetS(KMS_VAULT_KEY),
+		})
+
+	case KMS_AWS:
+		return awskms.NewProvider(&awskms.Config{
+			Endpoint:    knfu.GetS(KMS_AWS_ENDPOINT),
+			Region:      knfu.GetS(KMS_AWS_REGION),
+			AccessKeyID: knfu.GetS(KMS_AWS_ACCESS_KEY),
+			SecretKey:   knfu.GetS(KMS_AWS_SECRET_KEY),
+			KeyID:       knfu.GetS(KMS_AWS_KEY_ID),
+		})
+	}
+
+	return nil, fmt.Errorf("Unknown KMS provider %q", knfu.GetS(KMS_PROVIDER))
+}
+
+// getEncryptionType returns type of data encryption or empty string if encryption
+// is disabled
+func getEncryptionType() string {
+	encType := strings.ToLower(knfu.GetS(STORAGE_ENCRYPTION))
+
+	if encType == "" && (knfu.GetS(STORAGE_ENCRYPTION_KEY) != "" ||
+		knfu.GetS(STORAGE_ENCRYPTION_KEY_ID) != "") {
+		return ENCRYPTION_KATANA
+	}
+
+	return encType
+}
+
+// getEncryptionKeyIDs returns IDs of all named encryption keys
+func getEncryptionKeyIDs() []string {
+	return knfConfig.Props(ENCRYPTION_KEYS)
+}
+
+// getServerTokenNames returns names of all API clients with named tokens
+func getServerTokenNames() []string {
+	return knfConfig.Props(SERVER_TOKENS)
+}
+
+// getServerCertSubjects returns names of all client certificate subjects with
+// configured access
+func getServerCertSubjects() []string {
+	return knfConfig.Props(SERVER_CERTS)
+}
+
 // readPrivateKeyData reads private key data
 func readPrivateKeyData() ([]byte, error) {
 	if fsutil.IsExist(knfu.GetS(STORAGE_SFTP_KEY)) {
@@ -153,22 +321,33 @@ func readPrivateKeyData() ([]byte, error) {
 	return base64.StdEncoding.DecodeString(knfu.GetS(STORAGE_SFTP_KEY))
 }
 
-// sendUpdownPulse sends request to updown.io pulse
-func sendUpdownPulse(ok bool, payload string) {
-	if knfu.GetS(UPDOWN_PULSE_WEBHOOK) == "" {
-		return
+// getUploadStateDir returns path to directory with upload resume state or empty
+// string if resuming is disabled
+func getUploadStateDir() string {
+	if !knfu.GetB(STORAGE_RESUME, true) {
+		return ""
 	}
 
-	log.Info("Sending pulse request to updown.io…")
+	return getDataDir("uploads")
+}
 
-	uuid, err := updown.SendPulse(
-		knfu.GetS(UPDOWN_PULSE_WEBHOOK),
-		fmt.Sprintf("%s - %s", strutil.B(ok, "OK", "NOT-OK"), payload),
-	)
+// getDataDir returns path to directory with persistent data, creating it if
+// required. It returns empty string if persistent data directory is not configured.
+func getDataDir(name string) string {
+	if knfu.GetS(DATA_DIR) == "" {
+		return ""
+	}
 
-	if err != nil {
-		log.Error("Error while sending pulse: %v", err)
-	} else {
-		log.Info("Pulse successfully sent (%s)", uuid)
+	dir := path.Join(knfu.GetS(DATA_DIR), name)
+
+	if !fsutil.IsExist(dir) {
+		err := os.MkdirAll(dir, 0700)
+
+		if err != nil {
+			log.Error("Can't create directory %s: %v", dir, err)
+			return ""
+		}
 	}
+
+	return dir
 }
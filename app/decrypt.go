@@ -0,0 +1,248 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/essentialkaos/ek/v13/fmtc"
+	"github.com/essentialkaos/ek/v13/fsutil"
+	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/options"
+	"github.com/essentialkaos/ek/v13/path"
+
+	knfu "github.com/essentialkaos/ek/v13/knf/united"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor"
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor/envelope"
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor/katana"
+	"github.com/essentialkaos/atlassian-cloud-backuper/uploader"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// decryptBackup decrypts backup file using the key referenced in backup metadata
+func decryptBackup(args options.Arguments) error {
+	if !args.Has(2) {
+		return fmt.Errorf("You must define backup file and output file")
+	}
+
+	file := args.Get(1).Clean().String()
+	output := args.Get(2).Clean().String()
+
+	err := fsutil.ValidatePerms("FRS", file)
+
+	if err != nil {
+		return err
+	}
+
+	meta, err := readBackupMetadata(file)
+
+	if err != nil {
+		return err
+	}
+
+	if !meta.IsEncrypted() {
+		return fmt.Errorf("Backup %s is not encrypted", file)
+	}
+
+	if meta.Encryption.Type == ENCRYPTION_AGE {
+		return fmt.Errorf(
+			"Backup is encrypted with age, use private identity to decrypt it (age -d -i <identity> %s)",
+			file,
+		)
+	}
+
+	enc, err := getDecryptor(meta.Encryption)
+
+	if err != nil {
+		return err
+	}
+
+	log.Info(
+		"Decrypting backup file",
+		log.F{"backup-file", file}, log.F{"output-file", output},
+		log.F{"key-id", meta.Encryption.KeyID},
+		log.F{"key-fingerprint", meta.Encryption.Fingerprint},
+	)
+
+	err = decryptFile(enc, file, output)
+
+	if err != nil {
+		return err
+	}
+
+	log.Info("Backup successfully decrypted", log.F{"output-file", output})
+	fmtc.Printfn("{g}Backup successfully decrypted into {g*}%s{!}", output)
+
+	return nil
+}
+
+// readBackupMetadata reads backup metadata from sidecar file or from the header
+// of backup encrypted with envelope encryption if sidecar file is unavailable
+func readBackupMetadata(file string) (*uploader.Metadata, error) {
+	meta, err := uploader.ReadMetadata(file + uploader.META_EXT)
+
+	if err == nil {
+		return meta, nil
+	}
+
+	fd, ferr := os.Open(file)
+
+	if ferr != nil {
+		return nil, fmt.Errorf("Can't read backup metadata: %w", err)
+	}
+
+	defer fd.Close()
+
+	info, herr := envelope.ReadHeader(bufio.NewReader(fd))
+
+	if herr != nil || info == nil {
+		return nil, fmt.Errorf("Can't read backup metadata: %w", err)
+	}
+
+	log.Warn("Backup metadata is unavailable, using encryption info from backup header")
+
+	return &uploader.Metadata{File: path.Base(file), Encryption: info}, nil
+}
+
+// getDecryptor returns decryptor for backup with given encryption info
+func getDecryptor(info *encryptor.Info) (encryptor.Decryptor, error) {
+	switch info.Type {
+	case ENCRYPTION_KATANA:
+		return getDecryptionKey(info)
+	case ENCRYPTION_ENVELOPE:
+		return getEnvelopeDecryptor(info)
+	}
+
+	return nil, fmt.Errorf("Unsupported encryption type %q", info.Type)
+}
+
+// getEnvelopeDecryptor unwraps data key using KMS and returns decryptor
+func getEnvelopeDecryptor(info *encryptor.Info) (*envelope.EnvelopeEncryptor, error) {
+	if info.WrappedKey == "" {
+		return nil, fmt.Errorf("Backup metadata doesn't contain wrapped data key")
+	}
+
+	provider, err := getKMSProvider()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if provider.Name() != info.Provider {
+		return nil, fmt.Errorf(
+			"Data key was wrapped by %q KMS provider, but %q provider is configured",
+			info.Provider, provider.Name(),
+		)
+	}
+
+	log.Info(
+		"Unwrapping data key using KMS",
+		log.F{"kms-provider", info.Provider}, log.F{"kms-key", info.KeyID},
+	)
+
+	return envelope.NewDecryptor(provider, info.WrappedKey)
+}
+
+// getDecryptionKey returns encryptor with key which was used for backup encryption
+func getDecryptionKey(info *encryptor.Info) (*katana.KatanaEncryptor, error) {
+	keys := map[string]string{}
+
+	for _, keyID := range getEncryptionKeyIDs() {
+		keys[keyID] = knfu.GetS(ENCRYPTION_KEYS + ":" + keyID)
+	}
+
+	if knfu.GetS(STORAGE_ENCRYPTION_KEY) != "" {
+		keys[""] = knfu.GetS(STORAGE_ENCRYPTION_KEY)
+	}
+
+	if info.KeyID != "" && keys[info.KeyID] != "" {
+		return katana.NewEncryptor(info.KeyID, keys[info.KeyID])
+	}
+
+	if info.Fingerprint != "" {
+		for keyID, key := range keys {
+			if isKeyFingerprintMatch(key, info) {
+				return katana.NewEncryptor(keyID, key)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"Can't find encryption key with ID %q and fingerprint %q",
+		info.KeyID, info.Fingerprint,
+	)
+}
+
+// isKeyFingerprintMatch returns true if given key matches fingerprint from
+// encryption info
+func isKeyFingerprintMatch(key string, info *encryptor.Info) bool {
+	if info.Salt == "" {
+		return encryptor.LegacyFingerprint(key) == info.Fingerprint
+	}
+
+	fingerprint, err := encryptor.Fingerprint(key, info.Salt)
+
+	return err == nil && fingerprint == info.Fingerprint
+}
+
+// decryptFile decrypts file using given decryptor
+func decryptFile(enc encryptor.Decryptor, file, output string) error {
+	fd, err := os.Open(file)
+
+	if err != nil {
+		return fmt.Errorf("Can't open backup file: %w", err)
+	}
+
+	defer fd.Close()
+
+	r, err := enc.NewDecryptReader(bufio.NewReader(fd))
+
+	if err != nil {
+		return fmt.Errorf("Can't create decrypting reader: %w", err)
+	}
+
+	ofd, err := os.OpenFile(output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+
+	if err != nil {
+		return fmt.Errorf("Can't open output file: %w", err)
+	}
+
+	w := bufio.NewWriter(ofd)
+	_, err = io.Copy(w, r)
+
+	if err != nil {
+		err = fmt.Errorf("Can't decrypt backup file: %w", err)
+	}
+
+	if err == nil {
+		err = w.Flush()
+
+		if err != nil {
+			err = fmt.Errorf("Can't save output file: %w", err)
+		}
+	}
+
+	closeErr := ofd.Close()
+
+	if err == nil && closeErr != nil {
+		err = fmt.Errorf("Can't save output file: %w", closeErr)
+	}
+
+	if err != nil {
+		// Partially decrypted data is useless, so we remove it
+		os.Remove(output)
+		return err
+	}
+
+	return nil
+}
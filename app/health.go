@@ -0,0 +1,296 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"crypto/tls"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/fmtutil"
+	"github.com/essentialkaos/ek/v13/fsutil"
+	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/req"
+	"github.com/essentialkaos/ek/v13/strutil"
+	"github.com/essentialkaos/ek/v13/terminal"
+
+	knfu "github.com/essentialkaos/ek/v13/knf/united"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	HEALTH_OK   = "ok"
+	HEALTH_FAIL = "fail"
+)
+
+// HEALTH_MIN_TEMP_SPACE is minimal amount of free space in temporary directory
+const HEALTH_MIN_TEMP_SPACE = 1024 * 1024 * 1024
+
+// HEALTH_CHECK_TIMEOUT is max duration of a single health check
+const HEALTH_CHECK_TIMEOUT = 10 * time.Second
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// HealthResponse contains health check result
+type HealthResponse struct {
+	Status string         `json:"status"`
+	Checks []*HealthCheck `json:"checks,omitempty"`
+}
+
+// HealthCheck contains result of a single health check
+type HealthCheck struct {
+	Name     string  `json:"name"`
+	Status   string  `json:"status"`
+	Error    string  `json:"error,omitempty"`
+	Duration float64 `json:"duration"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// healthLiveHandler is handler for liveness probe
+func healthLiveHandler(rw http.ResponseWriter, r *http.Request) {
+	updateResponseHeaders(rw)
+	writeJSONResponse(rw, http.StatusOK, &HealthResponse{Status: HEALTH_OK})
+}
+
+// healthReadyHandler is handler for readiness probe
+func healthReadyHandler(rw http.ResponseWriter, r *http.Request) {
+	updateResponseHeaders(rw)
+
+	resp := runHealthChecks(map[string]func() error{
+		"jobs": checkJobManager,
+		"temp": checkTempDir,
+	})
+
+	writeHealthResponse(rw, resp)
+}
+
+// healthDeepHandler is handler for deep health check which checks access to
+// Atlassian API and storage
+func healthDeepHandler(rw http.ResponseWriter, r *http.Request) {
+	updateResponseHeaders(rw)
+
+	targets := []string{TARGET_JIRA, TARGET_CONFLUENCE}
+
+	if r.URL.Query().Has("target") {
+		target := strings.ToLower(r.URL.Query().Get("target"))
+		err := validateTarget(target)
+
+		if err != nil {
+			writeErrorResponse(rw, http.StatusBadRequest, err)
+			return
+		}
+
+		targets = []string{target}
+	}
+
+	for _, target := range targets {
+		if _, ok := authorize(rw, r, target, ACTION_STATUS); !ok {
+			return
+		}
+	}
+
+	// Checks use network and can take longer than server write timeout
+	err := http.NewResponseController(rw).SetWriteDeadline(
+		time.Now().Add(HEALTH_CHECK_TIMEOUT + 5*time.Second),
+	)
+
+	if err != nil {
+		log.Error("Can't extend write deadline for deep health check: %v", err)
+	}
+
+	checks := map[string]func() error{
+		"temp":       checkTempDir,
+		"temp-space": checkTempSpace,
+	}
+
+	for _, target := range targets {
+		checks[target+"-access"] = func() error { return checkAPIAccess(target) }
+		checks[target+"-storage"] = func() error { return checkStorage(target) }
+	}
+
+	writeHealthResponse(rw, runHealthChecks(checks))
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// runHealthcheck checks server readiness and returns exit code
+func runHealthcheck() int {
+	host := knfu.GetS(SERVER_IP)
+	port := strutil.Q(os.Getenv("PORT"), knfu.GetS(SERVER_PORT))
+
+	if host == "" || host == "0.0.0.0" {
+		host = "127.0.0.1"
+	}
+
+	scheme := "http"
+	engine := &req.Engine{}
+
+	if knfu.GetS(SERVER_TLS_CERT) != "" {
+		// Server certificate is usually issued for external name, so it can't be
+		// verified while connecting to loopback address
+		scheme = "https"
+		engine.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+
+	resp, err := engine.Init().Get(req.Request{
+		URL:         scheme + "://" + host + ":" + port + "/health/ready",
+		AutoDiscard: true,
+	})
+
+	if err != nil {
+		terminal.Error("Can't send request to server: %v", err)
+		return 1
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		terminal.Error("Server is not ready (status code: %d)", resp.StatusCode)
+		return 1
+	}
+
+	return 0
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// runHealthChecks runs all given checks
+func runHealthChecks(checks map[string]func() error) *HealthResponse {
+	var wg sync.WaitGroup
+
+	resp := &HealthResponse{Status: HEALTH_OK}
+	names := slices.Sorted(maps.Keys(checks))
+	results := make([]*HealthCheck, len(names))
+
+	// Checks are executed at the same time, so total duration is limited by
+	// the slowest check instead of the sum of all checks durations
+	for i, name := range names {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			results[i] = runHealthCheck(name, checks[name])
+		}()
+	}
+
+	wg.Wait()
+
+	for _, check := range results {
+		if check.Status != HEALTH_OK {
+			resp.Status = HEALTH_FAIL
+		}
+
+		resp.Checks = append(resp.Checks, check)
+	}
+
+	return resp
+}
+
+// runHealthCheck runs given check with timeout
+func runHealthCheck(name string, checkFunc func() error) *HealthCheck {
+	start := time.Now()
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- checkFunc() }()
+
+	var err error
+
+	select {
+	case err = <-errCh:
+	case <-time.After(HEALTH_CHECK_TIMEOUT):
+		err = fmt.Errorf("Check timed out after %g seconds", HEALTH_CHECK_TIMEOUT.Seconds())
+	}
+
+	check := &HealthCheck{
+		Name:     name,
+		Status:   HEALTH_OK,
+		Duration: time.Since(start).Seconds(),
+	}
+
+	if err != nil {
+		check.Status, check.Error = HEALTH_FAIL, err.Error()
+	}
+
+	return check
+}
+
+// writeHealthResponse writes health check response
+func writeHealthResponse(rw http.ResponseWriter, resp *HealthResponse) {
+	if resp.Status != HEALTH_OK {
+		writeJSONResponse(rw, http.StatusServiceUnavailable, resp)
+		return
+	}
+
+	writeJSONResponse(rw, http.StatusOK, resp)
+}
+
+// checkJobManager checks if jobs manager is initialized
+func checkJobManager() error {
+	if jobManager == nil {
+		return fmt.Errorf("Jobs manager is not initialized")
+	}
+
+	return nil
+}
+
+// checkTempDir checks temporary directory permissions
+func checkTempDir() error {
+	return fsutil.ValidatePerms("DWRX", temp.Dir)
+}
+
+// checkTempSpace checks free space in temporary directory
+func checkTempSpace() error {
+	stats := &syscall.Statfs_t{}
+	err := syscall.Statfs(temp.Dir, stats)
+
+	if err != nil {
+		return fmt.Errorf("Can't get file system info: %w", err)
+	}
+
+	free := stats.Bavail * uint64(stats.Bsize)
+
+	if free < HEALTH_MIN_TEMP_SPACE {
+		return fmt.Errorf(
+			"Not enough free space in temporary directory (%s < %s)",
+			fmtutil.PrettySize(free), fmtutil.PrettySize(HEALTH_MIN_TEMP_SPACE),
+		)
+	}
+
+	return nil
+}
+
+// checkAPIAccess checks access to Atlassian API for given target
+func checkAPIAccess(target string) error {
+	bkpr, err := getBackuper(target, nil)
+
+	if err != nil {
+		return err
+	}
+
+	return bkpr.CheckAccess()
+}
+
+// checkStorage checks storage availability for given target
+func checkStorage(target string) error {
+	updr, err := getUploader(target, nil)
+
+	if err != nil {
+		return err
+	}
+
+	return updr.Check()
+}
@@ -0,0 +1,48 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func TestRunHealthChecks(t *testing.T) {
+	slowCheck := func() error {
+		time.Sleep(300 * time.Millisecond)
+		return nil
+	}
+
+	start := time.Now()
+	resp := runHealthChecks(map[string]func() error{
+		"b": slowCheck,
+		"a": slowCheck,
+		"c": func() error { return errors.New("storage is unavailable") },
+	})
+
+	if time.Since(start) >= 600*time.Millisecond {
+		t.Fatalf("Checks must be executed at the same time (%s)", time.Since(start))
+	}
+
+	if resp.Status != HEALTH_FAIL || len(resp.Checks) != 3 {
+		t.Fatalf("Invalid response status %q", resp.Status)
+	}
+
+	for i, name := range []string{"a", "b", "c"} {
+		if resp.Checks[i].Name != name {
+			t.Fatalf("Checks are not sorted (%d: %s)", i, resp.Checks[i].Name)
+		}
+	}
+
+	if resp.Checks[2].Error != "storage is unavailable" {
+		t.Fatalf("Invalid check error %q", resp.Checks[2].Error)
+	}
+}
@@ -0,0 +1,227 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/fmtutil"
+	"github.com/essentialkaos/ek/v13/fmtutil/table"
+	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/options"
+	"github.com/essentialkaos/ek/v13/strutil"
+	"github.com/essentialkaos/ek/v13/terminal"
+	"github.com/essentialkaos/ek/v13/timeutil"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/history"
+	"github.com/essentialkaos/atlassian-cloud-backuper/jobs"
+	"github.com/essentialkaos/atlassian-cloud-backuper/notifier"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// runHistory is runs history store
+var runHistory *history.Store
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// setupHistory opens runs history store. History is disabled if data directory
+// is not set.
+func setupHistory() error {
+	dir := getDataDir("history")
+
+	if dir == "" {
+		return nil
+	}
+
+	var err error
+
+	runHistory, err = history.NewStore(dir)
+
+	if err != nil {
+		return fmt.Errorf("Can't open runs history: %w", err)
+	}
+
+	return nil
+}
+
+// recordRun adds info about backup run to history
+func recordRun(trigger string, n *notifier.Notification, start time.Time) {
+	if runHistory == nil {
+		return
+	}
+
+	r := &history.Record{
+		ID:        n.Job,
+		Target:    n.Target,
+		Trigger:   trigger,
+		JobType:   n.JobType,
+		TaskID:    n.TaskID,
+		Outcome:   history.OUTCOME_SUCCESS,
+		Error:     n.Error,
+		Duration:  n.Duration,
+		Durations: n.Durations,
+		File:      n.File,
+		Size:      n.Size,
+		Checksum:  n.Checksum,
+		Started:   start.UTC(),
+		Finished:  time.Now().UTC(),
+	}
+
+	if n.Error != "" {
+		r.Outcome = history.OUTCOME_FAILURE
+	}
+
+	if n.Location != "" {
+		r.Destinations = []string{n.Location}
+	}
+
+	err := runHistory.Add(r)
+
+	if err != nil {
+		log.Error("Can't save run to history: %v", err, log.F{"run-id", r.ID})
+	}
+}
+
+// recordJob adds info about finished server job to history
+func recordJob(info jobs.Info) {
+	n := getJobNotification(notifier.EVENT_SUCCESS, info)
+	trigger := history.TRIGGER_SERVER
+
+	// Full backup jobs are created only by scheduler, API creates separate
+	// jobs for creating and downloading backup
+	if info.Type == jobs.TYPE_BACKUP {
+		trigger = history.TRIGGER_CRON
+	}
+
+	if info.Phase == jobs.PHASE_FAILED && n.Error == "" {
+		n.Error = "Unknown error"
+	}
+
+	recordRun(trigger, n, info.Started)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// printHistory prints runs history
+func printHistory(args options.Arguments) int {
+	query, err := getHistoryQuery(args)
+
+	if err != nil {
+		terminal.Error(err)
+		return 1
+	}
+
+	store, err := history.NewStore(getDataDir("history"))
+
+	if err != nil {
+		terminal.Error("Can't open runs history: %v", err)
+		return 1
+	}
+
+	records, err := store.Find(query)
+
+	if err != nil {
+		terminal.Error(err)
+		return 1
+	}
+
+	if options.GetB(OPT_JSON) {
+		return printHistoryJSON(records)
+	}
+
+	if len(records) == 0 {
+		terminal.Warn("No runs found")
+		return 0
+	}
+
+	t := table.NewTable("FINISHED", "TARGET", "TRIGGER", "TYPE", "OUTCOME", "DURATION", "SIZE", "DETAILS")
+	t.SetAlignments(table.ALIGN_LEFT, table.ALIGN_LEFT, table.ALIGN_LEFT, table.ALIGN_LEFT,
+		table.ALIGN_LEFT, table.ALIGN_RIGHT, table.ALIGN_RIGHT, table.ALIGN_LEFT)
+
+	for _, r := range records {
+		outcome, details := "{g}success{!}", "—"
+
+		switch {
+		case !r.IsSuccessful():
+			outcome, details = "{r}failure{!}", "{r}"+strutil.Ellipsis(r.Error, 80)+"{!}"
+		case len(r.Destinations) != 0:
+			details = strings.Join(r.Destinations, ", ")
+		}
+
+		t.Add(
+			timeutil.Format(r.Finished.Local(), "%Y/%m/%d %H:%M"),
+			r.Target, r.Trigger, r.JobType, outcome,
+			timeutil.ShortDuration(time.Duration(r.Duration*float64(time.Second))),
+			strutil.B(r.Size > 0, fmtutil.PrettySize(r.Size), "—"),
+			details,
+		)
+	}
+
+	t.Render()
+
+	return 0
+}
+
+// printHistoryJSON prints runs history as JSON
+func printHistoryJSON(records []*history.Record) int {
+	if records == nil {
+		records = []*history.Record{}
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+
+	if err != nil {
+		terminal.Error("Can't encode history: %v", err)
+		return 1
+	}
+
+	fmt.Fprintln(os.Stdout, string(data))
+
+	return 0
+}
+
+// getHistoryQuery creates history query from command arguments and options
+func getHistoryQuery(args options.Arguments) (*history.Query, error) {
+	query := &history.Query{
+		Target:  args.Get(1).String(),
+		Trigger: options.GetS(OPT_TRIGGER),
+		Outcome: options.GetS(OPT_OUTCOME),
+		Limit:   options.GetI(OPT_LIMIT),
+	}
+
+	switch {
+	case query.Target != "" && query.Target != TARGET_JIRA && query.Target != TARGET_CONFLUENCE:
+		return nil, fmt.Errorf("Unknown target %q", query.Target)
+	case query.Trigger != "" && !slices.Contains([]string{
+		history.TRIGGER_CLI, history.TRIGGER_CRON, history.TRIGGER_SERVER,
+	}, query.Trigger):
+		return nil, fmt.Errorf("Unknown trigger %q", query.Trigger)
+	case query.Outcome != "" && !slices.Contains([]string{
+		history.OUTCOME_SUCCESS, history.OUTCOME_FAILURE,
+	}, query.Outcome):
+		return nil, fmt.Errorf("Unknown outcome %q", query.Outcome)
+	}
+
+	if options.Has(OPT_SINCE) {
+		dur, err := timeutil.ParseDuration(options.GetS(OPT_SINCE))
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid period %q: %w", options.GetS(OPT_SINCE), err)
+		}
+
+		query.Since = time.Now().Add(-dur)
+	}
+
+	return query, nil
+}
@@ -0,0 +1,210 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/events"
+	"github.com/essentialkaos/ek/v13/fsutil"
+	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/path"
+	"github.com/essentialkaos/ek/v13/req"
+
+	knfu "github.com/essentialkaos/ek/v13/knf/united"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/backuper"
+	"github.com/essentialkaos/atlassian-cloud-backuper/metrics"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// METRICS_PREFIX is prefix for all metrics names
+const METRICS_PREFIX = "atlassian_cloud_backuper_"
+
+// METRICS_JOB is name of job used for metrics files and Pushgateway groups
+const METRICS_JOB = "atlassian-cloud-backuper"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	metricsRegistry = metrics.NewRegistry()
+
+	metricLastSuccess = metricsRegistry.NewGauge(
+		METRICS_PREFIX+"last_success_timestamp_seconds",
+		"Time of the last successful backup", "target",
+	)
+
+	metricLastFailure = metricsRegistry.NewGauge(
+		METRICS_PREFIX+"last_failure_timestamp_seconds",
+		"Time of the last failed backup", "target",
+	)
+
+	metricLastRunSuccess = metricsRegistry.NewGauge(
+		METRICS_PREFIX+"last_run_success",
+		"Result of the last backup run (1 - success, 0 - failure)", "target",
+	)
+
+	metricPhaseDuration = metricsRegistry.NewGauge(
+		METRICS_PREFIX+"phase_duration_seconds",
+		"Duration of the last backup phase", "target", "phase",
+	)
+
+	metricArchiveSize = metricsRegistry.NewGauge(
+		METRICS_PREFIX+"archive_size_bytes",
+		"Size of the last uploaded backup archive", "target",
+	)
+
+	metricUploadThroughput = metricsRegistry.NewGauge(
+		METRICS_PREFIX+"upload_throughput_bytes_per_second",
+		"Average throughput of the last backup upload", "target",
+	)
+
+	metricAPIErrors = metricsRegistry.NewCounter(
+		METRICS_PREFIX+"api_errors_total",
+		"Number of Atlassian API errors", "target", "code",
+	)
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// metricsHandler is handler for metrics requests
+func metricsHandler(rw http.ResponseWriter, r *http.Request) {
+	updateResponseHeaders(rw)
+
+	rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	rw.WriteHeader(http.StatusOK)
+
+	metricsRegistry.WriteTo(rw)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// addMetricsHandlers registers events handlers for collecting metrics
+func addMetricsHandlers(dispatcher *events.Dispatcher, target string) {
+	dispatcher.AddHandler(backuper.EVENT_API_ERROR, func(payload any) {
+		metricAPIErrors.Inc(target, strconv.Itoa(payload.(int)))
+	})
+}
+
+// observePhase records duration of backup phase
+func observePhase(target, phase string, start time.Time) {
+	metricPhaseDuration.Set(time.Since(start).Seconds(), target, phase)
+}
+
+// observeUpload records size of uploaded backup and upload throughput
+func observeUpload(target string, size int64, start time.Time) {
+	metricArchiveSize.Set(float64(size), target)
+
+	dur := time.Since(start).Seconds()
+
+	if dur > 0 {
+		metricUploadThroughput.Set(float64(size)/dur, target)
+	}
+}
+
+// observeResult records result of backup process
+func observeResult(target string, err error) {
+	if err != nil {
+		metricLastFailure.Set(float64(time.Now().Unix()), target)
+		metricLastRunSuccess.Set(0, target)
+	} else {
+		metricLastSuccess.Set(float64(time.Now().Unix()), target)
+		metricLastRunSuccess.Set(1, target)
+	}
+}
+
+// exportRunMetrics writes metrics of CLI run to textfile collector directory and
+// pushes them to Pushgateway
+func exportRunMetrics(target string) {
+	if knfu.GetS(METRICS_TEXTFILE_DIR) != "" {
+		err := writeMetricsFile(target)
+
+		if err != nil {
+			log.Error("Can't write metrics file: %v", err)
+			notifyWarning(target, fmt.Sprintf("Can't write metrics file: %v", err))
+		}
+	}
+
+	if knfu.GetS(METRICS_PUSHGATEWAY_URL) != "" {
+		err := pushMetrics(target)
+
+		if err != nil {
+			log.Error("Can't push metrics to Pushgateway: %v", err)
+			notifyWarning(target, fmt.Sprintf("Can't push metrics to Pushgateway: %v", err))
+		}
+	}
+}
+
+// writeMetricsFile atomically writes metrics to textfile collector directory
+func writeMetricsFile(target string) error {
+	file := path.Join(knfu.GetS(METRICS_TEXTFILE_DIR), METRICS_JOB+"-"+target+".prom")
+
+	if fsutil.IsExist(file) {
+		restoreMetrics(file, target)
+	}
+
+	tmpFile := file + ".tmp"
+	err := os.WriteFile(tmpFile, metricsRegistry.EncodeFor("target", target), 0644)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmpFile, file)
+
+	if err != nil {
+		os.Remove(tmpFile)
+		return err
+	}
+
+	return nil
+}
+
+// restoreMetrics restores timestamps of previous runs from metrics file
+func restoreMetrics(file, target string) {
+	data, err := os.ReadFile(file)
+
+	if err != nil {
+		return
+	}
+
+	for _, m := range []*metrics.Metric{metricLastSuccess, metricLastFailure} {
+		if !m.Has(target) {
+			m.Restore(data, target)
+		}
+	}
+}
+
+// pushMetrics pushes metrics to Pushgateway
+func pushMetrics(target string) error {
+	url := strings.TrimRight(knfu.GetS(METRICS_PUSHGATEWAY_URL), "/") +
+		"/metrics/job/" + METRICS_JOB + "/target/" + target
+
+	resp, err := req.Request{
+		URL:         url,
+		ContentType: "text/plain; version=0.0.4",
+		Body:        metricsRegistry.EncodeFor("target", target),
+		AutoDiscard: true,
+	}.Post()
+
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Pushgateway returned non-ok status code (%d)", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,291 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/options"
+
+	knfu "github.com/essentialkaos/ek/v13/knf/united"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/jobs"
+	"github.com/essentialkaos/atlassian-cloud-backuper/notifier"
+	"github.com/essentialkaos/atlassian-cloud-backuper/notifier/chat"
+	"github.com/essentialkaos/atlassian-cloud-backuper/notifier/healthchecks"
+	"github.com/essentialkaos/atlassian-cloud-backuper/notifier/smtp"
+	"github.com/essentialkaos/atlassian-cloud-backuper/notifier/updown"
+	"github.com/essentialkaos/atlassian-cloud-backuper/notifier/webhook"
+	"github.com/essentialkaos/atlassian-cloud-backuper/runlog"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	// RECENT_LOG_LINES is max number of log records added to failure notifications
+	RECENT_LOG_LINES = 20
+
+	// DIGEST_CHECK_INTERVAL is interval of checking chat digest in server mode
+	DIGEST_CHECK_INTERVAL = 10 * time.Minute
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// notifications is notifications manager
+var notifications *notifier.Manager
+
+// chatNotifier is chat notifier which collects successful backups for digest
+var chatNotifier *chat.ChatNotifier
+
+// jobLogs contains logs of running jobs in server mode
+var jobLogs = map[string]*runlog.Log{}
+
+// jobLogsMu is jobs logs mutex
+var jobLogsMu sync.Mutex
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// setupNotifiers configures notifiers
+func setupNotifiers() error {
+	notifications = notifier.NewManager()
+
+	if knfu.GetS(UPDOWN_PULSE_WEBHOOK) != "" {
+		n, err := updown.NewNotifier(&updown.Config{
+			Webhook: knfu.GetS(UPDOWN_PULSE_WEBHOOK),
+		})
+
+		if err != nil {
+			return fmt.Errorf("Can't create updown.io notifier: %w", err)
+		}
+
+		err = notifications.Add(n, getNotifierEvents(
+			UPDOWN_PULSE_EVENTS, notifier.EVENT_SUCCESS, notifier.EVENT_FAILURE,
+		)...)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if knfu.GetS(NOTIFY_WEBHOOK_URL) != "" {
+		n, err := webhook.NewNotifier(&webhook.Config{
+			URL:      knfu.GetS(NOTIFY_WEBHOOK_URL),
+			Template: knfu.GetS(NOTIFY_WEBHOOK_TEMPLATE),
+			Timeout:  knfu.GetTD(NOTIFY_WEBHOOK_TIMEOUT),
+		})
+
+		if err != nil {
+			return fmt.Errorf("Can't create webhook notifier: %w", err)
+		}
+
+		err = notifications.Add(n, getNotifierEvents(NOTIFY_WEBHOOK_EVENTS)...)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if knfu.GetS(NOTIFY_SMTP_HOST) != "" {
+		n, err := smtp.NewNotifier(&smtp.Config{
+			Host:           knfu.GetS(NOTIFY_SMTP_HOST),
+			Port:           knfu.GetI(NOTIFY_SMTP_PORT),
+			TLS:            strings.ToLower(knfu.GetS(NOTIFY_SMTP_TLS)),
+			Username:       knfu.GetS(NOTIFY_SMTP_USERNAME),
+			Password:       knfu.GetS(NOTIFY_SMTP_PASSWORD),
+			From:           knfu.GetS(NOTIFY_SMTP_FROM),
+			To:             knfu.GetL(NOTIFY_SMTP_TO),
+			SuccessTo:      knfu.GetL(NOTIFY_SMTP_SUCCESS_TO),
+			FailureTo:      knfu.GetL(NOTIFY_SMTP_FAILURE_TO),
+			SuccessSubject: knfu.GetS(NOTIFY_SMTP_SUCCESS_SUBJECT),
+			FailureSubject: knfu.GetS(NOTIFY_SMTP_FAILURE_SUBJECT),
+			Timeout:        knfu.GetTD(NOTIFY_SMTP_TIMEOUT),
+		})
+
+		if err != nil {
+			return fmt.Errorf("Can't create SMTP notifier: %w", err)
+		}
+
+		err = notifications.Add(n, getNotifierEvents(
+			NOTIFY_SMTP_EVENTS, notifier.EVENT_SUCCESS, notifier.EVENT_FAILURE,
+		)...)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if knfu.GetS(NOTIFY_CHAT_TYPE) != "" {
+		digest := knfu.GetB(NOTIFY_CHAT_DIGEST, true)
+		stateDir := getDataDir("notify")
+
+		// Successful backups can't be collected for digest between CLI runs
+		// without persistent state
+		if digest && stateDir == "" && !options.GetB(OPT_SERVER) {
+			log.Warn("Chat digest is disabled because data directory is not set")
+			digest = false
+		}
+
+		n, err := chat.NewNotifier(&chat.Config{
+			Type:     strings.ToLower(knfu.GetS(NOTIFY_CHAT_TYPE)),
+			Webhook:  knfu.GetS(NOTIFY_CHAT_WEBHOOK),
+			APIURL:   knfu.GetS(NOTIFY_CHAT_API_URL),
+			Token:    knfu.GetS(NOTIFY_CHAT_TOKEN),
+			Channel:  knfu.GetS(NOTIFY_CHAT_CHANNEL),
+			Link:     knfu.GetS(NOTIFY_CHAT_LINK),
+			Digest:   digest,
+			StateDir: stateDir,
+			Timeout:  knfu.GetTD(NOTIFY_CHAT_TIMEOUT),
+		})
+
+		if err != nil {
+			return fmt.Errorf("Can't create chat notifier: %w", err)
+		}
+
+		err = notifications.Add(n, getNotifierEvents(
+			NOTIFY_CHAT_EVENTS, notifier.EVENT_SUCCESS, notifier.EVENT_FAILURE,
+		)...)
+
+		if err != nil {
+			return err
+		}
+
+		if digest {
+			chatNotifier = n
+		}
+	}
+
+	if knfu.GetS(NOTIFY_HEALTHCHECKS_URL) != "" {
+		n, err := healthchecks.NewNotifier(&healthchecks.Config{
+			URL:     knfu.GetS(NOTIFY_HEALTHCHECKS_URL),
+			Timeout: knfu.GetTD(NOTIFY_HEALTHCHECKS_TIMEOUT),
+		})
+
+		if err != nil {
+			return fmt.Errorf("Can't create healthchecks notifier: %w", err)
+		}
+
+		err = notifications.Add(n, getNotifierEvents(
+			NOTIFY_HEALTHCHECKS_EVENTS,
+			notifier.EVENT_START, notifier.EVENT_SUCCESS, notifier.EVENT_FAILURE,
+		)...)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runDigestLoop periodically sends chat digest if it is due
+func runDigestLoop() {
+	if chatNotifier == nil {
+		return
+	}
+
+	for range time.NewTicker(DIGEST_CHECK_INTERVAL).C {
+		err := chatNotifier.SendDigest(false)
+
+		if err != nil {
+			log.Error("Can't send chat digest: %v", err)
+		}
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// notifyJobStart sends notification about job start
+func notifyJobStart(info jobs.Info) {
+	notifications.Notify(getJobNotification(notifier.EVENT_START, info))
+}
+
+// notifyJobFinish sends notification about job result
+func notifyJobFinish(info jobs.Info) {
+	if info.Phase == jobs.PHASE_FAILED {
+		notifications.Notify(getJobNotification(notifier.EVENT_FAILURE, info))
+	} else {
+		notifications.Notify(getJobNotification(notifier.EVENT_SUCCESS, info))
+	}
+}
+
+// notifyBackupEvent sends notification with info about backup
+func notifyBackupEvent(event string, report *notifier.Notification) {
+	n := *report
+	n.Event = event
+
+	notifications.Notify(&n)
+}
+
+// notifyWarning sends warning notification
+func notifyWarning(target, message string) {
+	notifications.Notify(&notifier.Notification{
+		Event:   notifier.EVENT_WARNING,
+		Target:  target,
+		Account: knfu.GetS(ACCESS_ACCOUNT),
+		Message: message,
+	})
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getJobNotification creates notification with info about job
+func getJobNotification(event string, info jobs.Info) *notifier.Notification {
+	n := &notifier.Notification{
+		Event:     event,
+		Target:    info.Target,
+		Account:   knfu.GetS(ACCESS_ACCOUNT),
+		Job:       info.ID,
+		JobType:   info.Type,
+		TaskID:    info.TaskID,
+		Error:     info.Error,
+		Duration:  info.Duration,
+		Durations: info.Durations,
+	}
+
+	if info.Result != nil {
+		n.File = info.Result.File
+		n.Size = info.Result.Size
+		n.Checksum = info.Result.Checksum
+		n.Location = info.Result.Location
+	}
+
+	if event == notifier.EVENT_FAILURE {
+		n.Logs = getJobLog(info.ID).Records()
+	}
+
+	return n
+}
+
+// getNotifierEvents returns list of events from given option or default events
+// if option is empty
+func getNotifierEvents(option string, defEvents ...string) []string {
+	return notifier.ParseEvents(knfu.GetL(option, defEvents))
+}
+
+// getJobLog returns log of job with given ID
+func getJobLog(id string) *runlog.Log {
+	jobLogsMu.Lock()
+	defer jobLogsMu.Unlock()
+
+	if jobLogs[id] == nil {
+		jobLogs[id] = runlog.New(RECENT_LOG_LINES)
+	}
+
+	return jobLogs[id]
+}
+
+// removeJobLog removes log of finished job
+func removeJobLog(id string) {
+	jobLogsMu.Lock()
+	delete(jobLogs, id)
+	jobLogsMu.Unlock()
+}
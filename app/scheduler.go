@@ -0,0 +1,108 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/path"
+
+	knfu "github.com/essentialkaos/ek/v13/knf/united"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/jobs"
+	"github.com/essentialkaos/atlassian-cloud-backuper/scheduler"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// startScheduler starts scheduler for targets with configured backup schedule
+func startScheduler() error {
+	schedules := map[string]string{
+		TARGET_JIRA:       knfu.GetS(JIRA_SCHEDULE),
+		TARGET_CONFLUENCE: knfu.GetS(CONFLUENCE_SCHEDULE),
+	}
+
+	if schedules[TARGET_JIRA] == "" && schedules[TARGET_CONFLUENCE] == "" {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(knfu.GetS(SCHEDULER_TIMEZONE, "Local"))
+
+	if err != nil {
+		return fmt.Errorf("Can't load time zone: %w", err)
+	}
+
+	var stateFile string
+
+	if dataDir := getDataDir("scheduler"); dataDir != "" {
+		stateFile = path.Join(dataDir, "state.json")
+	}
+
+	schd, err := scheduler.NewScheduler(&scheduler.Config{
+		Location:  loc,
+		StateFile: stateFile,
+		CatchUp:   knfu.GetB(SCHEDULER_CATCH_UP, true),
+	})
+
+	if err != nil {
+		return fmt.Errorf("Can't create scheduler: %w", err)
+	}
+
+	for _, target := range []string{TARGET_JIRA, TARGET_CONFLUENCE} {
+		if schedules[target] == "" {
+			continue
+		}
+
+		err = schd.Add(
+			target, schedules[target],
+			knfu.GetTD(SCHEDULER_JITTER),
+			func() error { return runScheduledBackup(target) },
+		)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Info("Scheduler started", log.F{"timezone", loc.String()})
+
+	schd.Start()
+
+	return nil
+}
+
+// runScheduledBackup runs full backup pipeline for given target and waits
+// until it is finished
+func runScheduledBackup(target string) error {
+	job, err := jobManager.Add(jobs.TYPE_BACKUP, target, func(job *jobs.Job) error {
+		return backupJob(job, target)
+	})
+
+	if err != nil {
+		return fmt.Errorf("Can't create job: %w", err)
+	}
+
+	log.Info("Scheduled backup job added", log.F{"job-id", job.ID()})
+
+	addJobCallback(job, knfu.GetS(SERVER_CALLBACK_URL))
+
+	return job.Wait()
+}
+
+// backupJob creates backup, waits until it is ready and uploads it to storage
+func backupJob(job *jobs.Job, target string) error {
+	err := createBackupJob(job, target, false)
+
+	if err != nil {
+		return err
+	}
+
+	return downloadBackupJob(job, target)
+}
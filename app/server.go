@@ -8,29 +8,59 @@ package app
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/essentialkaos/ek/v13/events"
 	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/passthru"
 	"github.com/essentialkaos/ek/v13/strutil"
 
 	knfu "github.com/essentialkaos/ek/v13/knf/united"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/backuper"
+	"github.com/essentialkaos/atlassian-cloud-backuper/jobs"
+	"github.com/essentialkaos/atlassian-cloud-backuper/uploader"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+// ErrorResponse is response with error
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// jobManager is background jobs manager
+var jobManager *jobs.Manager
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
 // startServer starts app in server mode
 func startServer() error {
 	port := strutil.Q(os.Getenv("PORT"), knfu.GetS(SERVER_PORT))
 	ip := knfu.GetS(SERVER_IP)
 
+	tlsConfig, err := getServerTLSConfig()
+
+	if err != nil {
+		return err
+	}
+
 	log.Info(
 		"Starting HTTP server",
 		log.F{"server-ip", strutil.Q(ip, "localhost")},
 		log.F{"server-port", port},
+		log.F{"tls", tlsConfig != nil},
+		log.F{"mtls", isMTLSEnabled()},
 	)
 
 	mux := http.NewServeMux()
@@ -38,160 +68,423 @@ func startServer() error {
 	server := &http.Server{
 		Addr:         ip + ":" + port,
 		Handler:      mux,
+		TLSConfig:    tlsConfig,
 		ReadTimeout:  3 * time.Second,
 		WriteTimeout: 3 * time.Second,
 	}
 
+	err = loadAuthClients()
+
+	if err != nil {
+		return fmt.Errorf("Can't load access tokens: %w", err)
+	}
+
+	jobManager = jobs.NewManager(&jobs.Config{
+		StateDir: getDataDir("jobs"),
+		OnStart:  notifyJobStart,
+		OnFinish: onJobFinish,
+	})
+
+	restored, err := jobManager.Restore(getRestoredJobHandler)
+
+	if err != nil {
+		return fmt.Errorf("Can't restore jobs: %w", err)
+	}
+
+	if restored > 0 {
+		log.Info("Unfinished jobs restored", log.F{"jobs", restored})
+	}
+
 	mux.HandleFunc("/create", createBackupHandler)
 	mux.HandleFunc("/download", downloadBackupHandler)
+	mux.HandleFunc("GET /jobs/{id}", jobInfoHandler)
+	mux.HandleFunc("GET /jobs/{id}/events", jobEventsHandler)
+	mux.HandleFunc("GET /metrics", metricsHandler)
+	mux.HandleFunc("GET /health/live", healthLiveHandler)
+	mux.HandleFunc("GET /health/ready", healthReadyHandler)
+	mux.HandleFunc("GET /health/deep", healthDeepHandler)
+
+	err = startScheduler()
+
+	if err != nil {
+		return err
+	}
+
+	go runDigestLoop()
+
+	server.RegisterOnShutdown(func() { close(sseStop) })
 
-	return server.ListenAndServe()
+	shutdownErr := make(chan error, 1)
+	go handleShutdown(server, shutdownErr)
+
+	if tlsConfig != nil {
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+
+	if err != http.ErrServerClosed {
+		return err
+	}
+
+	return <-shutdownErr
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
-// createHandler is handler for caching booking data
+// createBackupHandler is handler for creating backup jobs
 func createBackupHandler(rw http.ResponseWriter, r *http.Request) {
 	updateResponseHeaders(rw)
 
 	log.Info("Got create request", getConfigurationFields())
 
 	target := strings.ToLower(r.URL.Query().Get("target"))
-	token := r.URL.Query().Get("token")
 	force := r.URL.Query().Get("force") != ""
 
-	err := validateRequestQuery(target, token)
+	err := validateTarget(target)
 
 	if err != nil {
 		log.Error("Invalid request query: %v", err.Error())
-		rw.WriteHeader(http.StatusBadRequest)
+		writeErrorResponse(rw, http.StatusBadRequest, err)
+		return
+	}
+
+	client, ok := authorize(rw, r, target, ACTION_CREATE)
+
+	if !ok {
 		return
 	}
 
-	bkpr, err := getBackuper(target)
+	callbackURL, err := getCallbackURL(r.URL.Query())
 
 	if err != nil {
-		sendUpdownPulse(false, err.Error())
-		log.Error("Can't create backuper instance: %v", err)
-		rw.WriteHeader(http.StatusInternalServerError)
+		log.Error("Invalid request query: %v", err.Error())
+		writeErrorResponse(rw, http.StatusBadRequest, err)
 		return
 	}
 
-	taskID, err := bkpr.Start(force)
+	job, err := jobManager.Add(jobs.TYPE_CREATE, target, func(job *jobs.Job) error {
+		return createBackupJob(job, target, force)
+	})
 
 	if err != nil {
-		sendUpdownPulse(false, err.Error())
-		log.Error("Can't create backup: %v", err)
-		rw.WriteHeader(http.StatusBadGateway)
+		log.Error("Can't create job: %v", err)
+		writeErrorResponse(rw, http.StatusInternalServerError, err)
 		return
 	}
 
-	log.Info("Backup request successfully created", log.F{"task-id", taskID})
+	auditLog(client, r, ACTION_CREATE, target, log.F{"job-id", job.ID()})
 
-	sendUpdownPulse(true, "create-backup")
+	addJobCallback(job, callbackURL)
 
-	rw.WriteHeader(http.StatusOK)
+	writeJSONResponse(rw, http.StatusAccepted, job.Info())
 }
 
-// createHandler is handler for caching booking data
+// downloadBackupHandler is handler for downloading backup jobs
 func downloadBackupHandler(rw http.ResponseWriter, r *http.Request) {
-	var lf log.Fields
-
 	updateResponseHeaders(rw)
 
 	log.Info("Got download request", getConfigurationFields())
 
 	target := strings.ToLower(r.URL.Query().Get("target"))
-	token := r.URL.Query().Get("token")
 
-	err := validateRequestQuery(target, token)
+	err := validateTarget(target)
 
 	if err != nil {
-		sendUpdownPulse(false, err.Error())
 		log.Error("Invalid request query: %v", err.Error())
-		rw.WriteHeader(http.StatusBadRequest)
+		writeErrorResponse(rw, http.StatusBadRequest, err)
+		return
+	}
+
+	client, ok := authorize(rw, r, target, ACTION_DOWNLOAD)
+
+	if !ok {
 		return
 	}
 
-	bkpr, err := getBackuper(target)
+	callbackURL, err := getCallbackURL(r.URL.Query())
 
 	if err != nil {
-		sendUpdownPulse(false, err.Error())
-		log.Error("Can't create backuper instance: %v", err)
-		rw.WriteHeader(http.StatusInternalServerError)
+		log.Error("Invalid request query: %v", err.Error())
+		writeErrorResponse(rw, http.StatusBadRequest, err)
 		return
 	}
 
-	backupFile, err := bkpr.GetBackupFile()
+	job, err := jobManager.Add(jobs.TYPE_DOWNLOAD, target, func(job *jobs.Job) error {
+		return downloadBackupJob(job, target)
+	})
 
 	if err != nil {
-		sendUpdownPulse(false, err.Error())
-		log.Error("Can't find backup file: %v", err)
-		rw.WriteHeader(http.StatusBadGateway)
+		log.Error("Can't create job: %v", err)
+		writeErrorResponse(rw, http.StatusInternalServerError, err)
 		return
 	}
 
-	log.Info("Starting downloading of backup", log.F{"backup-file", backupFile})
+	auditLog(client, r, ACTION_DOWNLOAD, target, log.F{"job-id", job.ID()})
+
+	addJobCallback(job, callbackURL)
+
+	writeJSONResponse(rw, http.StatusAccepted, job.Info())
+}
+
+// jobInfoHandler is handler for job status requests
+func jobInfoHandler(rw http.ResponseWriter, r *http.Request) {
+	updateResponseHeaders(rw)
+
+	job, ok := getRequestJob(rw, r)
+
+	if !ok {
+		return
+	}
+
+	writeJSONResponse(rw, http.StatusOK, job.Info())
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// createBackupJob starts backup creation and waits until backup is ready
+func createBackupJob(job *jobs.Job, target string, force bool) error {
+	start := time.Now()
+	err := createBackup(job, target, force)
+
+	observePhase(target, jobs.PHASE_CREATING, start)
+
+	if err != nil {
+		observeResult(target, err)
+	}
+
+	return err
+}
+
+// downloadBackupJob downloads created backup and uploads it to storage
+func downloadBackupJob(job *jobs.Job, target string) error {
+	err := downloadBackup(job, target)
+
+	observeResult(target, err)
+
+	return err
+}
+
+// createBackup starts backup creation and waits until backup is ready
+func createBackup(job *jobs.Job, target string, force bool) error {
+	bkpr, err := getBackuper(target, nil)
+
+	if err != nil {
+		return fmt.Errorf("Can't create backuper instance: %w", err)
+	}
+
+	rl := getJobLog(job.ID())
+
+	bkpr.SetDispatcher(getJobDispatcher(job, target))
+	bkpr.SetLogger(rl)
+	job.SetPhase(jobs.PHASE_CREATING)
+
+	taskID := job.Info().TaskID
+
+	if taskID != "" {
+		rl.Info("Reattaching to existing backup task", log.F{"task-id", taskID})
+	} else {
+		taskID, err = bkpr.Start(force)
+
+		if err != nil {
+			return fmt.Errorf("Can't create backup: %w", err)
+		}
+
+		job.SetTaskID(taskID)
+
+		rl.Info("Backup request successfully created", log.F{"task-id", taskID})
+	}
+
+	_, err = bkpr.Progress(taskID)
+
+	if err != nil {
+		return fmt.Errorf("Can't create backup: %w", err)
+	}
+
+	return nil
+}
+
+// downloadBackup downloads created backup and uploads it to storage
+func downloadBackup(job *jobs.Job, target string) error {
+	bkpr, err := getBackuper(target, nil)
+
+	if err != nil {
+		return fmt.Errorf("Can't create backuper instance: %w", err)
+	}
+
+	rl := getJobLog(job.ID())
+	dispatcher := getJobDispatcher(job, target)
+
+	bkpr.SetDispatcher(dispatcher)
+	bkpr.SetLogger(rl)
+	job.SetPhase(jobs.PHASE_DOWNLOADING)
+
+	backupFile, err := bkpr.GetBackupFile()
+
+	if err != nil {
+		return fmt.Errorf("Can't find backup file: %w", err)
+	}
+
+	rl.Info("Starting downloading of backup", log.F{"backup-file", backupFile})
 
 	br, err := bkpr.GetReader(backupFile)
 
 	if err != nil {
-		sendUpdownPulse(false, err.Error())
-		log.Error("Can't get reader for backup file: %v", err)
-		rw.WriteHeader(http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Can't get reader for backup file: %w", err)
 	}
 
-	updr, err := getUploader(target)
+	defer br.Close()
+
+	updr, err := getUploader(target, nil)
 
 	if err != nil {
-		sendUpdownPulse(false, err.Error())
-		log.Error("Can't create uploader instance: %v", err)
-		rw.WriteHeader(http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Can't create uploader instance: %w", err)
 	}
 
-	outputFile := getOutputFileName(target)
+	updr.SetDispatcher(dispatcher)
+	updr.SetLogger(rl)
 
-	lf.Add(
+	outputFile := getOutputFileName(target)
+	lf := []any{
+		log.F{"job-id", job.ID()},
 		log.F{"backup-file", backupFile},
 		log.F{"output-file", outputFile},
-	)
+	}
+
+	rl.Info("Uploading backup to storage", lf...)
+
+	job.SetPhase(jobs.PHASE_UPLOADING)
+
+	start := time.Now()
+	hasher := sha256.New()
+	pr := passthru.NewReader(io.TeeReader(job.Reader(br), hasher), 0)
+	lastUpdate := time.Now()
+	pr.Update = func(_ int) {
+		job.SetBytes(pr.Current(), 0)
 
-	log.Info("Uploading backup to storage", lf)
+		// Uploader doesn't send progress for data with unknown size
+		if time.Since(lastUpdate) >= time.Second {
+			dispatcher.Dispatch(
+				uploader.EVENT_UPLOAD_PROGRESS,
+				&uploader.ProgressInfo{Current: pr.Current()},
+			)
 
-	err = updr.Write(br, outputFile, 0)
+			lastUpdate = time.Now()
+		}
+	}
+
+	err = updr.Write(io.NopCloser(pr), outputFile, 0)
+
+	observePhase(target, jobs.PHASE_UPLOADING, start)
 
 	if err != nil {
-		sendUpdownPulse(false, err.Error())
-		log.Error("Can't upload backup file: %v", err, lf)
-		rw.WriteHeader(http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Can't upload backup file: %w", err)
 	}
 
-	log.Info("Backup successfully uploaded", lf)
+	observeUpload(target, pr.Current(), start)
+
+	job.SetResult(&jobs.Result{
+		File:     outputFile,
+		Size:     pr.Current(),
+		Checksum: "sha256:" + hex.EncodeToString(hasher.Sum(nil)),
+		Location: getStorageLocation(target, outputFile),
+	})
 
-	sendUpdownPulse(true, "upload-backup")
+	rl.Info("Backup successfully uploaded", lf...)
 
-	rw.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// getRestoredJobHandler returns handler for job restored after restart
+func getRestoredJobHandler(info jobs.Info) jobs.Handler {
+	var handler jobs.Handler
+
+	switch info.Type {
+	case jobs.TYPE_CREATE:
+		handler = func(job *jobs.Job) error { return createBackupJob(job, info.Target, false) }
+	case jobs.TYPE_DOWNLOAD:
+		handler = func(job *jobs.Job) error { return downloadBackupJob(job, info.Target) }
+	case jobs.TYPE_BACKUP:
+		handler = func(job *jobs.Job) error { return backupJob(job, info.Target) }
+	default:
+		return nil
+	}
+
+	return func(job *jobs.Job) error {
+		addJobCallback(job, strutil.Q(job.Callback(), knfu.GetS(SERVER_CALLBACK_URL)))
+		return handler(job)
+	}
+}
+
+// getJobDispatcher returns events dispatcher which updates job state and metrics
+func getJobDispatcher(job *jobs.Job, target string) *events.Dispatcher {
+	dispatcher := events.NewDispatcher()
+
+	dispatcher.AddHandler(backuper.EVENT_BACKUP_PROGRESS, func(payload any) {
+		p := payload.(*backuper.ProgressInfo)
+		job.SetProgress(float64(p.Progress), p.Message)
+	})
+
+	for _, event := range []string{
+		backuper.EVENT_BACKUP_STARTED, backuper.EVENT_BACKUP_PROGRESS,
+		backuper.EVENT_BACKUP_SAVING, backuper.EVENT_BACKUP_DONE,
+		uploader.EVENT_UPLOAD_STARTED, uploader.EVENT_UPLOAD_PROGRESS,
+		uploader.EVENT_UPLOAD_DONE,
+	} {
+		dispatcher.AddHandler(event, func(payload any) {
+			job.Publish(event, payload)
+		})
+	}
+
+	addMetricsHandlers(dispatcher, target)
+
+	return dispatcher
+}
+
+// onJobFinish saves finished job to history and sends notifications about
+// job result
+func onJobFinish(info jobs.Info) {
+	recordJob(info)
+	notifyJobFinish(info)
+	removeJobLog(info.ID)
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
-// validateRequestQuery validates request query arguments
-func validateRequestQuery(target, token string) error {
-	switch {
-	case target == "":
+// getRequestJob returns job with ID from request path if client has access to it.
+// If job can't be returned, error response is written.
+func getRequestJob(rw http.ResponseWriter, r *http.Request) (*jobs.Job, bool) {
+	client, ok := authorize(rw, r, "", ACTION_STATUS)
+
+	if !ok {
+		return nil, false
+	}
+
+	job := jobManager.Get(r.PathValue("id"))
+
+	if job == nil {
+		writeErrorResponse(rw, http.StatusNotFound, fmt.Errorf("Job not found"))
+		return nil, false
+	}
+
+	if !client.IsAllowed(job.Info().Target, ACTION_STATUS) {
+		writeErrorResponse(rw, http.StatusForbidden, ErrAuthForbidden)
+		return nil, false
+	}
+
+	return job, true
+}
+
+// validateTarget validates target name from request query
+func validateTarget(target string) error {
+	switch target {
+	case "":
 		return fmt.Errorf("target is empty")
-	case knfu.GetS(SERVER_ACCESS_TOKEN) != "" && token == "":
-		return fmt.Errorf("token is empty")
-	case target != TARGET_JIRA && target != TARGET_CONFLUENCE:
-		return fmt.Errorf("Unknown target %q", target)
-	case knfu.GetS(SERVER_ACCESS_TOKEN) != "" && token == knfu.GetS(SERVER_ACCESS_TOKEN):
-		return fmt.Errorf("Invalid access token")
+	case TARGET_JIRA, TARGET_CONFLUENCE:
+		return nil
 	}
 
-	return nil
+	return fmt.Errorf("Unknown target %q", target)
 }
 
 // getConfigurationFields returns log fields
@@ -230,6 +523,23 @@ func getConfigurationFields() *log.Fields {
 	return lf
 }
 
+// writeJSONResponse writes response with given data encoded as JSON
+func writeJSONResponse(rw http.ResponseWriter, code int, data any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+
+	err := json.NewEncoder(rw).Encode(data)
+
+	if err != nil {
+		log.Error("Can't encode response data: %v", err)
+	}
+}
+
+// writeErrorResponse writes JSON response with given error
+func writeErrorResponse(rw http.ResponseWriter, code int, err error) {
+	writeJSONResponse(rw, code, &ErrorResponse{Error: err.Error()})
+}
+
 // updateResponseHeaders updates response headers
 func updateResponseHeaders(rw http.ResponseWriter) {
 	rw.Header().Set("X-Powered-By", "EK|"+APP)
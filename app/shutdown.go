@@ -0,0 +1,87 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/signal"
+
+	knfu "github.com/essentialkaos/ek/v13/knf/united"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// SHUTDOWN_REQUESTS_TIMEOUT is max time to wait for active HTTP requests
+const SHUTDOWN_REQUESTS_TIMEOUT = 10 * time.Second
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// handleShutdown waits for TERM or INT signal and gracefully stops server
+func handleShutdown(server *http.Server, errCh chan error) {
+	sigCh := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case sigCh <- struct{}{}:
+		default:
+		}
+	}
+
+	signal.Handlers{
+		signal.TERM: notify,
+		signal.INT:  notify,
+	}.Track()
+
+	<-sigCh
+
+	errCh <- shutdownServer(server)
+}
+
+// shutdownServer stops accepting new requests, waits until running jobs are
+// finished and cancels jobs which weren't finished within grace period
+func shutdownServer(server *http.Server) error {
+	gracePeriod := knfu.GetTD(SERVER_GRACE_PERIOD, 5*time.Minute)
+
+	log.Info("Got shutdown signal, stopping server…", log.F{"grace-period", gracePeriod.String()})
+
+	ctx, cancel := context.WithTimeout(context.Background(), SHUTDOWN_REQUESTS_TIMEOUT)
+	defer cancel()
+
+	err := server.Shutdown(ctx)
+
+	if err != nil {
+		log.Error("Can't gracefully stop HTTP server: %v", err)
+	}
+
+	unfinished := jobManager.Shutdown(gracePeriod)
+
+	temp.Clean()
+
+	if len(unfinished) == 0 {
+		log.Info("Server stopped, all jobs finished")
+		return nil
+	}
+
+	for _, info := range unfinished {
+		log.Warn(
+			"Job wasn't finished before shutdown",
+			log.F{"job-id", info.ID}, log.F{"job-type", info.Type},
+			log.F{"target", info.Target}, log.F{"phase", info.Phase},
+		)
+
+		notifyWarning(info.Target, fmt.Sprintf(
+			"Job %s (%s) wasn't finished before server shutdown", info.ID, info.Type,
+		))
+	}
+
+	return fmt.Errorf("Server stopped with %d unfinished job(s)", len(unfinished))
+}
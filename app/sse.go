@@ -0,0 +1,109 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/log"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/jobs"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// SSE_KEEPALIVE_INTERVAL is interval for sending keepalive comments
+const SSE_KEEPALIVE_INTERVAL = 15 * time.Second
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// sseStop is closed when server is shutting down to stop all events streams
+var sseStop = make(chan struct{})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// jobEventsHandler is handler for streaming job events using Server-Sent Events
+func jobEventsHandler(rw http.ResponseWriter, r *http.Request) {
+	updateResponseHeaders(rw)
+
+	job, ok := getRequestJob(rw, r)
+
+	if !ok {
+		return
+	}
+
+	rc := http.NewResponseController(rw)
+
+	// Stream can be much longer than server write timeout
+	err := rc.SetWriteDeadline(time.Time{})
+
+	if err != nil {
+		log.Error("Can't disable write deadline for events stream: %v", err)
+		writeErrorResponse(rw, http.StatusInternalServerError, fmt.Errorf("Streaming is not supported"))
+		return
+	}
+
+	events, unsubscribe := job.Subscribe()
+	defer unsubscribe()
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("X-Accel-Buffering", "no")
+	rw.WriteHeader(http.StatusOK)
+
+	rc.Flush()
+
+	keepalive := time.NewTicker(SSE_KEEPALIVE_INTERVAL)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-sseStop:
+			return
+
+		case <-keepalive.C:
+			_, err = fmt.Fprint(rw, ": keepalive\n\n")
+
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			err = writeSSEEvent(rw, event)
+		}
+
+		if err == nil {
+			err = rc.Flush()
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// writeSSEEvent writes job event using Server-Sent Events format
+func writeSSEEvent(rw http.ResponseWriter, event *jobs.Event) error {
+	data, err := json.Marshal(event.Data)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(rw, "event: %s\ndata: %s\n\n", event.Type, data)
+
+	return err
+}
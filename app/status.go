@@ -0,0 +1,226 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/fmtc"
+	"github.com/essentialkaos/ek/v13/fmtutil"
+	"github.com/essentialkaos/ek/v13/options"
+	"github.com/essentialkaos/ek/v13/strutil"
+	"github.com/essentialkaos/ek/v13/terminal"
+	"github.com/essentialkaos/ek/v13/timeutil"
+
+	knfu "github.com/essentialkaos/ek/v13/knf/united"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/backuper"
+	"github.com/essentialkaos/atlassian-cloud-backuper/history"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// STATUS_WATCH_INTERVAL is interval between backup status checks in watch mode
+const STATUS_WATCH_INTERVAL = 15 * time.Second
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// printStatus prints info about backup state on Atlassian side
+func printStatus(args options.Arguments) int {
+	target := args.Get(1).String()
+
+	if target == "" {
+		terminal.Error("You must define target (%s or %s)", TARGET_JIRA, TARGET_CONFLUENCE)
+		return 1
+	}
+
+	setupReq()
+
+	bkpr, err := getBackuper(target, nil)
+
+	if err != nil {
+		terminal.Error(err)
+		return 1
+	}
+
+	err = bkpr.CheckAccess()
+
+	if err != nil {
+		terminal.Error("Can't access %s API: %v", target, err)
+		return 1
+	}
+
+	status, err := getBackupStatus(bkpr, target)
+
+	if err != nil {
+		terminal.Error(err)
+		return 1
+	}
+
+	if options.GetB(OPT_WATCH) && status.IsRunning {
+		status, err = watchBackupStatus(bkpr, target, status)
+
+		if err != nil {
+			terminal.Error(err)
+			return 1
+		}
+	}
+
+	printBackupStatus(target, status)
+
+	return 0
+}
+
+// watchBackupStatus prints backup progress until backup task is finished
+func watchBackupStatus(bkpr backuper.Backuper, target string, status *backuper.Status) (*backuper.Status, error) {
+	var err error
+
+	fmtc.NewLine()
+
+	for {
+		fmtc.TPrintf(
+			"{s}(%3d%%){!} Backup in progress: %s",
+			status.Progress, strutil.Q(status.Message, "—"),
+		)
+
+		if !status.IsRunning {
+			break
+		}
+
+		time.Sleep(STATUS_WATCH_INTERVAL)
+
+		status, err = getBackupStatus(bkpr, target)
+
+		if err != nil {
+			fmtc.NewLine()
+			return nil, err
+		}
+	}
+
+	fmtc.NewLine()
+
+	return status, nil
+}
+
+// printBackupStatus prints backup status info
+func printBackupStatus(target string, status *backuper.Status) {
+	fmtc.NewLine()
+	fmtc.Printfn(
+		"Backup status for {*}%s{!} in account {*}%s{!}",
+		target, knfu.GetS(ACCESS_ACCOUNT),
+	)
+	fmtc.NewLine()
+
+	if !status.HasTask() {
+		fmtc.Printfn("  {s}Task:{!}        {s}no backup task{!}")
+	} else {
+		fmtc.Printfn("  {s}Task:{!}        %s", strutil.Q(status.TaskID, "—"))
+	}
+
+	switch {
+	case status.IsReady && status.IsOutdated:
+		fmtc.Printfn("  {s}State:{!}       {y}outdated{!}")
+	case status.IsReady:
+		fmtc.Printfn("  {s}State:{!}       {g}ready for download{!}")
+	case status.IsRunning:
+		fmtc.Printfn(
+			"  {s}State:{!}       {c}in progress{!} {s}(%d%%){!} %s",
+			status.Progress, status.Message,
+		)
+	case status.HasTask():
+		fmtc.Printfn("  {s}State:{!}       {r}failed{!} %s", status.Message)
+	default:
+		fmtc.Printfn("  {s}State:{!}       —")
+	}
+
+	switch {
+	case status.IsReady && status.Size > 0:
+		fmtc.Printfn(
+			"  {s}File:{!}        %s {s-}(%s){!}",
+			status.File, fmtutil.PrettySize(status.Size),
+		)
+	case status.IsReady:
+		fmtc.Printfn("  {s}File:{!}        %s", status.File)
+	}
+
+	if !status.Created.IsZero() {
+		fmtc.Printfn(
+			"  {s}Created:{!}     %s {s-}(%s ago){!}",
+			timeutil.Format(status.Created.Local(), "%Y/%m/%d %H:%M"),
+			timeutil.PrettyDurationSimple(time.Since(status.Created).Truncate(time.Minute)),
+		)
+	}
+
+	nextWindow := status.NextWindow()
+
+	switch {
+	case !status.HasTask():
+		fmtc.Printfn("  {s}Next window:{!} {g}open{!}")
+	case nextWindow.IsZero():
+		fmtc.Printfn("  {s}Next window:{!} {s}unknown{!}")
+	case time.Now().After(nextWindow):
+		fmtc.Printfn("  {s}Next window:{!} {g}open{!}")
+	default:
+		fmtc.Printfn(
+			"  {s}Next window:{!} %s {s-}(in %s){!}",
+			timeutil.Format(nextWindow.Local(), "%Y/%m/%d %H:%M"),
+			timeutil.PrettyDurationSimple(time.Until(nextWindow).Truncate(time.Minute)),
+		)
+	}
+
+	fmtc.NewLine()
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getBackupStatus returns backup status with creation date taken from runs
+// history if API doesn't provide it
+func getBackupStatus(bkpr backuper.Backuper, target string) (*backuper.Status, error) {
+	status, err := bkpr.GetStatus()
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't get backup status: %w", err)
+	}
+
+	if status.Created.IsZero() && status.TaskID != "" {
+		status.Created = getTaskStartDate(target, status.TaskID)
+	}
+
+	return status, nil
+}
+
+// getTaskStartDate returns date of the first run which used backup task with
+// given ID
+func getTaskStartDate(target, taskID string) time.Time {
+	dir := getDataDir("history")
+
+	if dir == "" {
+		return time.Time{}
+	}
+
+	store, err := history.NewStore(dir)
+
+	if err != nil {
+		return time.Time{}
+	}
+
+	records, _ := store.Find(&history.Query{Target: target})
+
+	var started time.Time
+
+	// Records are sorted from newest to oldest, so the last one is the run
+	// which created the task
+	for _, r := range records {
+		if r.TaskID == taskID {
+			started = r.Started
+		}
+	}
+
+	return started
+}
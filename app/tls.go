@@ -0,0 +1,149 @@
+package app
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/fsutil"
+	"github.com/essentialkaos/ek/v13/log"
+
+	knfu "github.com/essentialkaos/ek/v13/knf/united"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// TLS_RELOAD_INTERVAL is minimal interval between checks for certificate changes
+const TLS_RELOAD_INTERVAL = 30 * time.Second
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// certReloader loads TLS certificate and reloads it if certificate or key
+// file was changed
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+
+	mu sync.Mutex
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getServerTLSConfig returns TLS configuration for HTTP server or nil if TLS
+// is disabled
+func getServerTLSConfig() (*tls.Config, error) {
+	if knfu.GetS(SERVER_TLS_CERT) == "" {
+		return nil, nil
+	}
+
+	reloader := &certReloader{
+		certFile: knfu.GetS(SERVER_TLS_CERT),
+		keyFile:  knfu.GetS(SERVER_TLS_KEY),
+	}
+
+	err := reloader.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	if knfu.GetS(SERVER_TLS_CLIENT_CA) == "" {
+		return config, nil
+	}
+
+	caData, err := os.ReadFile(knfu.GetS(SERVER_TLS_CLIENT_CA))
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't read client CA certificate: %w", err)
+	}
+
+	config.ClientCAs = x509.NewCertPool()
+
+	if !config.ClientCAs.AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("Can't parse client CA certificate")
+	}
+
+	// Certificate is verified if given, and required by authorization for all
+	// protected endpoints, so health probes still work without it
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+
+	return config, nil
+}
+
+// isMTLSEnabled returns true if client certificates are required
+func isMTLSEnabled() bool {
+	return knfu.GetS(SERVER_TLS_CERT) != "" && knfu.GetS(SERVER_TLS_CLIENT_CA) != ""
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// GetCertificate returns current certificate
+func (r *certReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) < TLS_RELOAD_INTERVAL {
+		return r.cert, nil
+	}
+
+	r.checked = time.Now()
+
+	if !r.getModTime().After(r.modTime) {
+		return r.cert, nil
+	}
+
+	err := r.load()
+
+	if err != nil {
+		log.Error("Can't reload TLS certificate: %v", err)
+	} else {
+		log.Info("TLS certificate reloaded", log.F{"tls-cert", r.certFile})
+	}
+
+	return r.cert, nil
+}
+
+// load loads certificate and key from files
+func (r *certReloader) load() error {
+	modTime := r.getModTime()
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+
+	if err != nil {
+		return fmt.Errorf("Can't load TLS certificate: %w", err)
+	}
+
+	r.cert, r.modTime, r.checked = &cert, modTime, time.Now()
+
+	return nil
+}
+
+// getModTime returns the latest modification time of certificate and key
+func (r *certReloader) getModTime() time.Time {
+	certTime, _ := fsutil.GetMTime(r.certFile)
+	keyTime, _ := fsutil.GetMTime(r.keyFile)
+
+	if keyTime.After(certTime) {
+		return keyTime
+	}
+
+	return certTime
+}
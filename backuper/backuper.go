@@ -10,8 +10,12 @@ package backuper
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/essentialkaos/ek/v13/events"
+	"github.com/essentialkaos/ek/v13/log"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -21,8 +25,12 @@ const (
 	EVENT_BACKUP_PROGRESS = "backup-progress"
 	EVENT_BACKUP_SAVING   = "backup-saving"
 	EVENT_BACKUP_DONE     = "backup-done"
+	EVENT_API_ERROR       = "api-error"
 )
 
+// BACKUP_INTERVAL is minimal interval between backups allowed by Atlassian
+const BACKUP_INTERVAL = 48 * time.Hour
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 // Backuper is generic backuper interface
@@ -33,6 +41,9 @@ type Backuper interface {
 	// SetDispatcher sets events dispatcher
 	SetDispatcher(d *events.Dispatcher)
 
+	// SetLogger sets logger for process messages
+	SetLogger(l log.ILogger)
+
 	// Start creates task for backuping data
 	Start(force bool) (string, error)
 
@@ -50,6 +61,17 @@ type Backuper interface {
 
 	// IsBackupCreated returns true if backup created and ready for download
 	IsBackupCreated() (bool, error)
+
+	// GetStatus returns info about current backup state without starting
+	// a new backup
+	GetStatus() (*Status, error)
+
+	// CheckAccess checks access to API with configured credentials
+	CheckAccess() error
+
+	// CheckPermissions checks if user has administrator permissions required
+	// for creating backups
+	CheckPermissions() error
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -61,11 +83,27 @@ type Config struct {
 	APIKey          string
 	WithAttachments bool
 	ForCloud        bool
+
+	// Encryptor is used for encrypting backup data saved to local disk
+	Encryptor encryptor.Encryptor
 }
 
 type ProgressInfo struct {
-	Message  string
-	Progress int
+	Message  string `json:"message"`
+	Progress int    `json:"progress"`
+}
+
+// Status contains info about backup state on Atlassian side
+type Status struct {
+	TaskID     string
+	Message    string
+	Progress   int
+	File       string
+	Size       int64
+	Created    time.Time
+	IsRunning  bool
+	IsReady    bool
+	IsOutdated bool
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -97,3 +135,20 @@ func (c Config) Validate() error {
 func (c Config) AccountURL() string {
 	return "https://" + c.Account + ".atlassian.net"
 }
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// HasTask returns true if there is any backup task
+func (s *Status) HasTask() bool {
+	return s != nil && (s.TaskID != "" || s.IsRunning || s.IsReady)
+}
+
+// NextWindow returns date when Atlassian will allow to create a new backup.
+// Zero time is returned if backup creation date is unknown.
+func (s *Status) NextWindow() time.Time {
+	if s == nil || s.Created.IsZero() {
+		return time.Time{}
+	}
+
+	return s.Created.Add(BACKUP_INTERVAL)
+}
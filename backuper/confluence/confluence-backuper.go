@@ -30,6 +30,7 @@ import (
 type ConfluenceBackuper struct {
 	config     *backuper.Config
 	dispatcher *events.Dispatcher
+	logger     log.ILogger
 }
 
 type BackupPrefs struct {
@@ -37,6 +38,13 @@ type BackupPrefs struct {
 	ForCloud        bool `json:"exportToCloud"`
 }
 
+type UserInfo struct {
+	Operations []struct {
+		Operation  string `json:"operation"`
+		TargetType string `json:"targetType"`
+	} `json:"operations"`
+}
+
 type BackupProgressInfo struct {
 	CurrentStatus              string `json:"currentStatus"`
 	AlternativePercentage      string `json:"alternativePercentage"`
@@ -61,7 +69,7 @@ func NewBackuper(config *backuper.Config) (*ConfluenceBackuper, error) {
 		return nil, err
 	}
 
-	return &ConfluenceBackuper{config, nil}, nil
+	return &ConfluenceBackuper{config: config, logger: log.Global}, nil
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -73,6 +81,19 @@ func (b *ConfluenceBackuper) SetDispatcher(d *events.Dispatcher) {
 	}
 }
 
+// SetLogger sets logger for process messages
+func (b *ConfluenceBackuper) SetLogger(l log.ILogger) {
+	if b == nil {
+		return
+	}
+
+	if l == nil {
+		l = log.Global
+	}
+
+	b.logger = l
+}
+
 // Backup starts backup process
 func (b *ConfluenceBackuper) Backup(outputFile string, force bool) error {
 	_, err := b.Start(force)
@@ -92,17 +113,17 @@ func (b *ConfluenceBackuper) Backup(outputFile string, force bool) error {
 
 // Start creates task for backuping data
 func (b *ConfluenceBackuper) Start(force bool) (string, error) {
-	log.Info(
+	b.logger.Info(
 		"Starting Confluence backup process for account %s (forced: %t)…",
 		b.config.Account, force,
 	)
 
-	log.Info("Checking for existing backup task…")
+	b.logger.Info("Checking for existing backup task…")
 
 	info, _ := b.getBackupProgress()
 
 	if !force && info != nil && !info.IsOutdated {
-		log.Info(
+		b.logger.Info(
 			"Found previously created backup task",
 			log.F{"backup-status", info.CurrentStatus},
 			log.F{"backup-perc", info.AlternativePercentage},
@@ -112,9 +133,9 @@ func (b *ConfluenceBackuper) Start(force bool) (string, error) {
 		)
 	} else {
 		if force {
-			log.Info("Starting new backup (force: %t)…", force)
+			b.logger.Info("Starting new backup (force: %t)…", force)
 		} else {
-			log.Info("No previously created backup task or task is outdated, starting new backup…")
+			b.logger.Info("No previously created backup task or task is outdated, starting new backup…")
 		}
 
 		err := b.startBackup()
@@ -141,7 +162,7 @@ func (b *ConfluenceBackuper) Progress(taskID string) (string, error) {
 		progressInfo, err := b.getBackupProgress()
 
 		if err != nil {
-			log.Error("Got error while checking progress: %v", err)
+			b.logger.Error("Got error while checking progress: %v", err)
 			errNum++
 
 			if errNum > 10 {
@@ -160,7 +181,7 @@ func (b *ConfluenceBackuper) Progress(taskID string) (string, error) {
 		b.dispatcher.Dispatch(backuper.EVENT_BACKUP_PROGRESS, b.convertProgressInfo(progressInfo))
 
 		if progressInfo.Size == 0 && progressInfo.AlternativePercentage >= lastProgress {
-			log.Info(
+			b.logger.Info(
 				"(%s%%) Backup in progress: %s",
 				progressInfo.AlternativePercentage,
 				progressInfo.CurrentStatus,
@@ -199,10 +220,40 @@ func (b *ConfluenceBackuper) GetBackupFile() (string, error) {
 	return progressInfo.Filename, nil
 }
 
+// GetStatus returns info about current backup state without starting
+// a new backup
+func (b *ConfluenceBackuper) GetStatus() (*backuper.Status, error) {
+	progressInfo, err := b.getBackupProgress()
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't get backup progress: %w", err)
+	}
+
+	p := b.convertProgressInfo(progressInfo)
+	status := &backuper.Status{
+		Message:    p.Message,
+		Progress:   p.Progress,
+		File:       progressInfo.Filename,
+		Size:       int64(progressInfo.Size),
+		IsReady:    progressInfo.Size != 0 && progressInfo.Filename != "",
+		IsOutdated: progressInfo.IsOutdated,
+	}
+
+	// Time contains date of backup creation as Unix timestamp in milliseconds
+	if progressInfo.Time > 0 {
+		status.Created = time.UnixMilli(int64(progressInfo.Time))
+	}
+
+	status.IsRunning = progressInfo.ConcurrentBackupInProgress ||
+		(!status.IsReady && !status.IsOutdated && progressInfo.CurrentStatus != "")
+
+	return status, nil
+}
+
 // Download downloads backup file
 func (b *ConfluenceBackuper) Download(backupFile, outputFile string) error {
-	log.Info("Backup is ready for download, fetching file…")
-	log.Info("Writing backup file into %s", outputFile)
+	b.logger.Info("Backup is ready for download, fetching file…")
+	b.logger.Info("Writing backup file into %s", outputFile)
 
 	b.dispatcher.DispatchAndWait(backuper.EVENT_BACKUP_SAVING, nil)
 
@@ -214,7 +265,7 @@ func (b *ConfluenceBackuper) Download(backupFile, outputFile string) error {
 
 	b.dispatcher.DispatchAndWait(backuper.EVENT_BACKUP_DONE, nil)
 
-	log.Info(
+	b.logger.Info(
 		"Backup successfully saved (size: %s)",
 		fmtutil.PrettySize(fsutil.GetSize(outputFile)),
 	)
@@ -226,7 +277,7 @@ func (b *ConfluenceBackuper) Download(backupFile, outputFile string) error {
 func (b *ConfluenceBackuper) GetReader(backupFile string) (io.ReadCloser, error) {
 	backupFileURL := b.config.AccountURL() + "/wiki/download/" + backupFile
 
-	log.Debug("Downloading file from %s", backupFileURL)
+	b.logger.Debug("Downloading file from %s", backupFileURL)
 
 	resp, err := req.Request{
 		URL:         backupFileURL,
@@ -239,12 +290,70 @@ func (b *ConfluenceBackuper) GetReader(backupFile string) (io.ReadCloser, error)
 	}
 
 	if resp.StatusCode != 200 {
+		b.dispatcher.Dispatch(backuper.EVENT_API_ERROR, resp.StatusCode)
 		return nil, fmt.Errorf("API returned non-ok status code (%d)", resp.StatusCode)
 	}
 
 	return resp.Body, nil
 }
 
+// CheckAccess checks access to API with configured credentials
+func (b *ConfluenceBackuper) CheckAccess() error {
+	resp, err := req.Request{
+		URL:         b.config.AccountURL() + "/wiki/rest/api/user/current",
+		Auth:        req.AuthBasic{b.config.Email, b.config.APIKey},
+		Accept:      req.CONTENT_TYPE_JSON,
+		AutoDiscard: true,
+	}.Get()
+
+	if err != nil {
+		return fmt.Errorf("Can't send request to API: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		b.dispatcher.Dispatch(backuper.EVENT_API_ERROR, resp.StatusCode)
+		return fmt.Errorf("API returned non-ok status code (%d)", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// CheckPermissions checks if user has administrator permissions required
+// for creating backups
+func (b *ConfluenceBackuper) CheckPermissions() error {
+	resp, err := req.Request{
+		URL:         b.config.AccountURL() + "/wiki/rest/api/user/current",
+		Auth:        req.AuthBasic{b.config.Email, b.config.APIKey},
+		Accept:      req.CONTENT_TYPE_JSON,
+		Query:       req.Query{"expand": "operations"},
+		AutoDiscard: true,
+	}.Get()
+
+	if err != nil {
+		return fmt.Errorf("Can't send request to API: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		b.dispatcher.Dispatch(backuper.EVENT_API_ERROR, resp.StatusCode)
+		return fmt.Errorf("API returned non-ok status code (%d)", resp.StatusCode)
+	}
+
+	userInfo := &UserInfo{}
+	err = resp.JSON(userInfo)
+
+	if err != nil {
+		return fmt.Errorf("Can't decode API response: %v", err)
+	}
+
+	for _, op := range userInfo.Operations {
+		if op.Operation == "administer" && op.TargetType == "application" {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("User %s doesn't have Confluence administrator permissions", b.config.Email)
+}
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 // startBackup starts backup process
@@ -265,6 +374,7 @@ func (b *ConfluenceBackuper) startBackup() error {
 	}
 
 	if resp.StatusCode != 200 {
+		b.dispatcher.Dispatch(backuper.EVENT_API_ERROR, resp.StatusCode)
 		return fmt.Errorf("API returned non-ok status code (%d)", resp.StatusCode)
 	}
 
@@ -286,6 +396,7 @@ func (b *ConfluenceBackuper) getBackupProgress() (*BackupProgressInfo, error) {
 	}
 
 	if resp.StatusCode != 200 {
+		b.dispatcher.Dispatch(backuper.EVENT_API_ERROR, resp.StatusCode)
 		return nil, fmt.Errorf("API returned non-ok status code (%d)", resp.StatusCode)
 	}
 
@@ -334,14 +445,35 @@ func (b *ConfluenceBackuper) downloadBackup(backupFile, outputFile string) error
 
 	defer fd.Close()
 
-	w := bufio.NewWriter(fd)
+	var w io.Writer
+	var ew io.WriteCloser
+
+	bw := bufio.NewWriter(fd)
+	w = bw
+
+	if b.config.Encryptor != nil {
+		ew, err = b.config.Encryptor.NewWriter(bw)
+
+		if err != nil {
+			return fmt.Errorf("Can't create encrypted writer: %w", err)
+		}
+
+		w = ew
+	}
+
 	_, err = io.Copy(w, r)
 
 	if err != nil {
 		return fmt.Errorf("File writing error: %w", err)
 	}
 
-	w.Flush()
+	if ew != nil {
+		err = ew.Close()
 
-	return nil
+		if err != nil {
+			return fmt.Errorf("Can't finalize encrypted data: %w", err)
+		}
+	}
+
+	return bw.Flush()
 }
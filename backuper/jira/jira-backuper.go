@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/essentialkaos/ek/v13/events"
@@ -28,6 +29,7 @@ import (
 type JiraBackuper struct {
 	config     *backuper.Config
 	dispatcher *events.Dispatcher
+	logger     log.ILogger
 }
 
 type BackupPrefs struct {
@@ -39,6 +41,12 @@ type BackupTaskInfo struct {
 	TaskID string `json:"taskId"`
 }
 
+type PermissionsInfo struct {
+	Permissions map[string]struct {
+		HavePermission bool `json:"havePermission"`
+	} `json:"permissions"`
+}
+
 type BackupProgressInfo struct {
 	Status     string `json:"status"`
 	Desc       string `json:"description"`
@@ -62,7 +70,7 @@ func NewBackuper(config *backuper.Config) (*JiraBackuper, error) {
 		return nil, err
 	}
 
-	return &JiraBackuper{config, nil}, nil
+	return &JiraBackuper{config: config, logger: log.Global}, nil
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -74,6 +82,19 @@ func (b *JiraBackuper) SetDispatcher(d *events.Dispatcher) {
 	}
 }
 
+// SetLogger sets logger for process messages
+func (b *JiraBackuper) SetLogger(l log.ILogger) {
+	if b == nil {
+		return
+	}
+
+	if l == nil {
+		l = log.Global
+	}
+
+	b.logger = l
+}
+
 // Backup starts backup process
 func (b *JiraBackuper) Backup(outputFile string, force bool) error {
 	backupTaskID, err := b.Start(force)
@@ -96,21 +117,21 @@ func (b *JiraBackuper) Start(force bool) (string, error) {
 	var err error
 	var backupTaskID string
 
-	log.Info(
+	b.logger.Info(
 		"Starting Jira backup process for account %s (forced: %t)…",
 		b.config.Account, force,
 	)
 
 	if !force {
-		log.Info("Checking for existing backup task…")
+		b.logger.Info("Checking for existing backup task…")
 
 		backupTaskID, _ = b.getLastTaskID()
 
 		if backupTaskID == "" {
-			log.Info("No previously created task found, starting new backup…")
+			b.logger.Info("No previously created task found, starting new backup…")
 		}
 	} else {
-		log.Info("Starting new backup…")
+		b.logger.Info("Starting new backup…")
 	}
 
 	if backupTaskID == "" {
@@ -121,7 +142,7 @@ func (b *JiraBackuper) Start(force bool) (string, error) {
 		}
 	}
 
-	b.dispatcher.DispatchAndWait(backuper.EVENT_BACKUP_STARTED, nil)
+	b.dispatcher.DispatchAndWait(backuper.EVENT_BACKUP_STARTED, backupTaskID)
 
 	return backupTaskID, nil
 }
@@ -138,7 +159,7 @@ func (b *JiraBackuper) Progress(taskID string) (string, error) {
 		progressInfo, err := b.getTaskProgress(taskID)
 
 		if err != nil {
-			log.Error("Got error while checking progress: %v", err)
+			b.logger.Error("Got error while checking progress: %v", err)
 			errNum++
 
 			if errNum > 10 {
@@ -164,7 +185,7 @@ func (b *JiraBackuper) Progress(taskID string) (string, error) {
 		)
 
 		if progressInfo.Progress < 100 && progressInfo.Progress >= lastProgress {
-			log.Info("(%d%%) Backup in progress: %s", progressInfo.Progress, progressInfo.Message)
+			b.logger.Info("(%d%%) Backup in progress: %s", progressInfo.Progress, progressInfo.Message)
 			lastProgress = progressInfo.Progress
 		}
 
@@ -211,10 +232,42 @@ func (b *JiraBackuper) GetBackupFile() (string, error) {
 	return progressInfo.Result, nil
 }
 
+// GetStatus returns info about current backup state without starting
+// a new backup
+func (b *JiraBackuper) GetStatus() (*backuper.Status, error) {
+	backupTaskID, err := b.getLastTaskID()
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't get last backup task ID: %w", err)
+	}
+
+	if backupTaskID == "" {
+		return &backuper.Status{}, nil
+	}
+
+	progressInfo, err := b.getTaskProgress(backupTaskID)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't get backup task progress: %w", err)
+	}
+
+	status := &backuper.Status{
+		TaskID:   backupTaskID,
+		Message:  progressInfo.Message,
+		Progress: progressInfo.Progress,
+		File:     progressInfo.Result,
+		IsReady:  progressInfo.Progress >= 100 && progressInfo.Result != "",
+	}
+
+	status.IsRunning = !status.IsReady && !strings.EqualFold(progressInfo.Status, "Failed")
+
+	return status, nil
+}
+
 // Download downloads backup file
 func (b *JiraBackuper) Download(backupFile, outputFile string) error {
-	log.Info("Backup is ready for download, fetching file…")
-	log.Info("Writing backup file into %s", outputFile)
+	b.logger.Info("Backup is ready for download, fetching file…")
+	b.logger.Info("Writing backup file into %s", outputFile)
 
 	b.dispatcher.DispatchAndWait(backuper.EVENT_BACKUP_SAVING, nil)
 
@@ -226,7 +279,7 @@ func (b *JiraBackuper) Download(backupFile, outputFile string) error {
 
 	b.dispatcher.DispatchAndWait(backuper.EVENT_BACKUP_DONE, nil)
 
-	log.Info(
+	b.logger.Info(
 		"Backup successfully saved (size: %s)",
 		fmtutil.PrettySize(fsutil.GetSize(outputFile)),
 	)
@@ -238,7 +291,7 @@ func (b *JiraBackuper) Download(backupFile, outputFile string) error {
 func (b *JiraBackuper) GetReader(backupFile string) (io.ReadCloser, error) {
 	backupFileURL := b.config.AccountURL() + "/plugins/servlet/" + backupFile
 
-	log.Debug("Downloading file from %s", backupFileURL)
+	b.logger.Debug("Downloading file from %s", backupFileURL)
 
 	resp, err := req.Request{
 		URL:         backupFileURL,
@@ -251,12 +304,68 @@ func (b *JiraBackuper) GetReader(backupFile string) (io.ReadCloser, error) {
 	}
 
 	if resp.StatusCode != 200 {
+		b.dispatcher.Dispatch(backuper.EVENT_API_ERROR, resp.StatusCode)
 		return nil, fmt.Errorf("API returned non-ok status code (%d)", resp.StatusCode)
 	}
 
 	return resp.Body, nil
 }
 
+// CheckAccess checks access to API with configured credentials
+func (b *JiraBackuper) CheckAccess() error {
+	resp, err := req.Request{
+		URL:         b.config.AccountURL() + "/rest/api/3/myself",
+		Auth:        req.AuthBasic{b.config.Email, b.config.APIKey},
+		Accept:      req.CONTENT_TYPE_JSON,
+		AutoDiscard: true,
+	}.Get()
+
+	if err != nil {
+		return fmt.Errorf("Can't send request to API: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		b.dispatcher.Dispatch(backuper.EVENT_API_ERROR, resp.StatusCode)
+		return fmt.Errorf("API returned non-ok status code (%d)", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// CheckPermissions checks if user has administrator permissions required
+// for creating backups
+func (b *JiraBackuper) CheckPermissions() error {
+	resp, err := req.Request{
+		URL:         b.config.AccountURL() + "/rest/api/3/mypermissions",
+		Auth:        req.AuthBasic{b.config.Email, b.config.APIKey},
+		Accept:      req.CONTENT_TYPE_JSON,
+		Query:       req.Query{"permissions": "ADMINISTER"},
+		AutoDiscard: true,
+	}.Get()
+
+	if err != nil {
+		return fmt.Errorf("Can't send request to API: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		b.dispatcher.Dispatch(backuper.EVENT_API_ERROR, resp.StatusCode)
+		return fmt.Errorf("API returned non-ok status code (%d)", resp.StatusCode)
+	}
+
+	permsInfo := &PermissionsInfo{}
+	err = resp.JSON(permsInfo)
+
+	if err != nil {
+		return fmt.Errorf("Can't decode API response: %v", err)
+	}
+
+	if !permsInfo.Permissions["ADMINISTER"].HavePermission {
+		return fmt.Errorf("User %s doesn't have Jira administrator permissions", b.config.Email)
+	}
+
+	return nil
+}
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 // startBackup starts backup process
@@ -277,6 +386,7 @@ func (b *JiraBackuper) startBackup() (string, error) {
 	}
 
 	if resp.StatusCode != 200 {
+		b.dispatcher.Dispatch(backuper.EVENT_API_ERROR, resp.StatusCode)
 		return "", fmt.Errorf("API returned non-ok status code (%d)", resp.StatusCode)
 	}
 
@@ -305,6 +415,7 @@ func (b *JiraBackuper) getLastTaskID() (string, error) {
 	}
 
 	if resp.StatusCode != 200 {
+		b.dispatcher.Dispatch(backuper.EVENT_API_ERROR, resp.StatusCode)
 		return "", fmt.Errorf("API returned non-ok status code (%d)", resp.StatusCode)
 	}
 
@@ -327,6 +438,7 @@ func (b *JiraBackuper) getTaskProgress(taskID string) (*BackupProgressInfo, erro
 	}
 
 	if resp.StatusCode != 200 {
+		b.dispatcher.Dispatch(backuper.EVENT_API_ERROR, resp.StatusCode)
 		return nil, fmt.Errorf("API returned non-ok status code (%d)", resp.StatusCode)
 	}
 
@@ -358,14 +470,35 @@ func (b *JiraBackuper) downloadBackup(backupFile, outputFile string) error {
 
 	defer fd.Close()
 
-	w := bufio.NewWriter(fd)
+	var w io.Writer
+	var ew io.WriteCloser
+
+	bw := bufio.NewWriter(fd)
+	w = bw
+
+	if b.config.Encryptor != nil {
+		ew, err = b.config.Encryptor.NewWriter(bw)
+
+		if err != nil {
+			return fmt.Errorf("Can't create encrypted writer: %w", err)
+		}
+
+		w = ew
+	}
+
 	_, err = io.Copy(w, r)
 
 	if err != nil {
 		return fmt.Errorf("File writing error: %w", err)
 	}
 
-	w.Flush()
+	if ew != nil {
+		err = ew.Close()
 
-	return nil
+		if err != nil {
+			return fmt.Errorf("Can't finalize encrypted data: %w", err)
+		}
+	}
+
+	return bw.Flush()
 }
@@ -57,6 +57,7 @@ rm -rf %{buildroot}
 install -dDm 755 %{buildroot}%{_bindir}
 install -dDm 755 %{buildroot}%{_sysconfdir}/logrotate.d
 install -dDm 755 %{buildroot}%{_localstatedir}/log/%{name}
+install -dDm 700 %{buildroot}%{_sharedstatedir}/%{name}
 
 install -pm 755 %{name}/%{name} \
                 %{buildroot}%{_bindir}/
@@ -111,6 +112,7 @@ systemctl daemon-reload &>/dev/null || :
 %defattr(-,root,root,-)
 %doc %{name}/LICENSE
 %dir %{_localstatedir}/log/%{name}
+%dir %{_sharedstatedir}/%{name}
 %config(noreplace) %{_sysconfdir}/%{name}.knf
 %config(noreplace) %{_sysconfdir}/logrotate.d/%{name}
 %config(noreplace) %{_unitdir}/%{name}-*
@@ -0,0 +1,140 @@
+package age
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/essentialkaos/ek/v13/fsutil"
+
+	"filippo.io/age"
+	"filippo.io/age/agessh"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// AgeEncryptor is encryptor which uses age public-key encryption
+type AgeEncryptor struct {
+	recipients []age.Recipient
+	keys       []string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// validate encryptor interface
+var _ encryptor.Encryptor = (*AgeEncryptor)(nil)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewEncryptor creates new age encryptor instance for given recipients. Every
+// recipient can be age X25519 public key, SSH public key or path to file with
+// recipients.
+func NewEncryptor(recipients []string) (*AgeEncryptor, error) {
+	e := &AgeEncryptor{}
+
+	for _, r := range recipients {
+		var err error
+
+		if fsutil.IsExist(r) {
+			err = e.readRecipientsFile(r)
+		} else {
+			err = e.addRecipient(r)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(e.recipients) == 0 {
+		return nil, fmt.Errorf("Configuration validation error: no recipients for age encryption")
+	}
+
+	return e, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Info returns info about used encryption
+func (e *AgeEncryptor) Info() *encryptor.Info {
+	return &encryptor.Info{
+		Type:       "age",
+		Recipients: e.keys,
+	}
+}
+
+// NewWriter creates new writer which encrypts all data written to given writer
+func (e *AgeEncryptor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return age.Encrypt(w, e.recipients...)
+}
+
+// NewReader creates new reader which encrypts all data read from given reader
+func (e *AgeEncryptor) NewReader(r io.Reader) (io.Reader, error) {
+	return encryptor.NewPipeReader(r, e), nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// addRecipient parses recipient and adds it to recipients list
+func (e *AgeEncryptor) addRecipient(recipient string) error {
+	var err error
+	var r age.Recipient
+
+	switch {
+	case strings.HasPrefix(recipient, "age1"):
+		r, err = age.ParseX25519Recipient(recipient)
+	case strings.HasPrefix(recipient, "ssh-"):
+		r, err = agessh.ParseRecipient(recipient)
+	default:
+		return fmt.Errorf("Unsupported age recipient %q", recipient)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Can't parse age recipient %q: %w", recipient, err)
+	}
+
+	e.recipients = append(e.recipients, r)
+	e.keys = append(e.keys, recipient)
+
+	return nil
+}
+
+// readRecipientsFile reads recipients from file
+func (e *AgeEncryptor) readRecipientsFile(file string) error {
+	fd, err := os.Open(file)
+
+	if err != nil {
+		return fmt.Errorf("Can't open recipients file: %w", err)
+	}
+
+	defer fd.Close()
+
+	s := bufio.NewScanner(fd)
+
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		err = e.addRecipient(line)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.Err()
+}
@@ -0,0 +1,100 @@
+package encryptor
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/essentialkaos/ek/v13/hashutil"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Encryptor is generic encryptor interface
+type Encryptor interface {
+	// Info returns info about used encryption
+	Info() *Info
+
+	// NewWriter creates new writer which encrypts all data written to given writer
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+
+	// NewReader creates new reader which encrypts all data read from given reader
+	NewReader(r io.Reader) (io.Reader, error)
+}
+
+// Decryptor is generic interface for encryptors which support decryption
+type Decryptor interface {
+	// NewDecryptReader creates new reader which decrypts all data read from given reader
+	NewDecryptReader(r io.Reader) (io.Reader, error)
+}
+
+// Info contains info about encryption used for backup
+type Info struct {
+	Type        string   `json:"type"`
+	KeyID       string   `json:"key_id,omitempty"`
+	Fingerprint string   `json:"fingerprint,omitempty"`
+	Salt        string   `json:"salt,omitempty"`
+	Recipients  []string `json:"recipients,omitempty"`
+	Provider    string   `json:"provider,omitempty"`
+	WrappedKey  string   `json:"wrapped_key,omitempty"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Fingerprint returns fingerprint for given symmetric key and salt. Fingerprint is
+// derived with scrypt, so it's expensive to use it for brute-forcing the key.
+func Fingerprint(key, salt string) (string, error) {
+	data, err := scrypt.Key([]byte(key), []byte("atlassian-cloud-backuper:"+salt), 1<<15, 8, 1, 8)
+
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(data), nil
+}
+
+// LegacyFingerprint returns unsalted fingerprint for given symmetric key. Such
+// fingerprints were saved by previous versions and are used only for finding
+// the key for old backups.
+func LegacyFingerprint(key string) string {
+	return hashutil.String("atlassian-cloud-backuper:"+key, sha256.New()).String()[:16]
+}
+
+// NewSalt returns new random salt for fingerprint
+func NewSalt() string {
+	salt := make([]byte, 16)
+	rand.Read(salt)
+	return hex.EncodeToString(salt)
+}
+
+// NewPipeReader creates encrypting reader for encryptors which support only
+// encrypting writers
+func NewPipeReader(r io.Reader, e Encryptor) io.Reader {
+	pr, pw := io.Pipe()
+
+	go func() {
+		ew, err := e.NewWriter(pw)
+
+		if err == nil {
+			_, err = io.Copy(ew, r)
+		}
+
+		if err == nil {
+			err = ew.Close()
+		}
+
+		pw.CloseWithError(err)
+	}()
+
+	return pr
+}
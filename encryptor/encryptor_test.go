@@ -0,0 +1,43 @@
+package encryptor
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"testing"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func TestFingerprint(t *testing.T) {
+	salt1, salt2 := NewSalt(), NewSalt()
+
+	if salt1 == salt2 || len(salt1) != 32 {
+		t.Fatalf("Invalid salt %q", salt1)
+	}
+
+	fp1, err := Fingerprint("MySuperSecretKey1234", salt1)
+
+	if err != nil {
+		t.Fatalf("Can't create fingerprint: %v", err)
+	}
+
+	fp2, _ := Fingerprint("MySuperSecretKey1234", salt2)
+	fp3, _ := Fingerprint("MySuperSecretKey1234", salt1)
+
+	if fp1 == fp2 {
+		t.Fatal("Fingerprints with different salt must be different")
+	}
+
+	if fp1 != fp3 {
+		t.Fatal("Fingerprints with the same salt must be equal")
+	}
+
+	if fp1 == LegacyFingerprint("MySuperSecretKey1234") {
+		t.Fatal("Fingerprint must not match legacy fingerprint")
+	}
+}
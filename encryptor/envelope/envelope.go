@@ -0,0 +1,223 @@
+package envelope
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/essentialkaos/katana"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor"
+	"github.com/essentialkaos/atlassian-cloud-backuper/kms"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DATA_KEY_SIZE is size of generated data key
+const DATA_KEY_SIZE = 32
+
+// HEADER_MAGIC is magic string which marks header with wrapped data key at the
+// beginning of encrypted data
+const HEADER_MAGIC = "ACB-ENVELOPE-1\n"
+
+// MAX_HEADER_SIZE is max size of header data
+const MAX_HEADER_SIZE = 64 * 1024
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// EnvelopeEncryptor is encryptor which encrypts data with random data key wrapped
+// by KMS provider
+type EnvelopeEncryptor struct {
+	secret     *katana.Secret
+	provider   kms.Provider
+	wrappedKey string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// validate encryptor interface
+var _ encryptor.Encryptor = (*EnvelopeEncryptor)(nil)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewEncryptor creates new envelope encryptor with new random data key
+func NewEncryptor(provider kms.Provider) (*EnvelopeEncryptor, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("KMS provider is nil")
+	}
+
+	key := make([]byte, DATA_KEY_SIZE)
+	_, err := rand.Read(key)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't generate data key: %w", err)
+	}
+
+	wrappedKey, err := provider.Wrap(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &EnvelopeEncryptor{
+		secret:     katana.NewSecret(base64.StdEncoding.EncodeToString(key)),
+		provider:   provider,
+		wrappedKey: wrappedKey,
+	}, nil
+}
+
+// NewDecryptor creates new envelope encryptor for decrypting data using wrapped
+// data key
+func NewDecryptor(provider kms.Provider, wrappedKey string) (*EnvelopeEncryptor, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("KMS provider is nil")
+	}
+
+	key, err := provider.Unwrap(wrappedKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &EnvelopeEncryptor{
+		secret:     katana.NewSecret(base64.StdEncoding.EncodeToString(key)),
+		provider:   provider,
+		wrappedKey: wrappedKey,
+	}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Info returns info about used encryption
+func (e *EnvelopeEncryptor) Info() *encryptor.Info {
+	return &encryptor.Info{
+		Type:       "envelope",
+		KeyID:      e.provider.KeyID(),
+		Provider:   e.provider.Name(),
+		WrappedKey: e.wrappedKey,
+	}
+}
+
+// NewWriter creates new writer which encrypts all data written to given writer.
+// Header with wrapped data key is written before encrypted data.
+func (e *EnvelopeEncryptor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	header, err := e.encodeHeader()
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = w.Write(header)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't write header: %w", err)
+	}
+
+	return e.secret.NewWriter(w)
+}
+
+// NewReader creates new reader which encrypts all data read from given reader.
+// Header with wrapped data key is returned before encrypted data.
+func (e *EnvelopeEncryptor) NewReader(r io.Reader) (io.Reader, error) {
+	header, err := e.encodeHeader()
+
+	if err != nil {
+		return nil, err
+	}
+
+	sr, err := e.secret.NewReader(r, katana.MODE_ENCRYPT)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return io.MultiReader(bytes.NewReader(header), sr), nil
+}
+
+// NewDecryptReader creates new reader which decrypts all data read from given reader
+func (e *EnvelopeEncryptor) NewDecryptReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	// Header is skipped if present (backups created by previous versions
+	// don't have it)
+	_, err := ReadHeader(br)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return e.secret.NewReader(br, katana.MODE_DECRYPT)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ReadHeader reads info about encryption from the header at the beginning of
+// encrypted data. It returns nil if data has no header.
+func ReadHeader(r *bufio.Reader) (*encryptor.Info, error) {
+	magic, err := r.Peek(len(HEADER_MAGIC))
+
+	if err != nil || string(magic) != HEADER_MAGIC {
+		return nil, nil
+	}
+
+	r.Discard(len(HEADER_MAGIC))
+
+	var size uint32
+
+	err = binary.Read(r, binary.BigEndian, &size)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't read header: %w", err)
+	}
+
+	if size > MAX_HEADER_SIZE {
+		return nil, fmt.Errorf("Can't read header: header is too big (%d bytes)", size)
+	}
+
+	data := make([]byte, size)
+	_, err = io.ReadFull(r, data)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't read header: %w", err)
+	}
+
+	info := &encryptor.Info{}
+	err = json.Unmarshal(data, info)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't decode header: %w", err)
+	}
+
+	return info, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// encodeHeader encodes header with info about encryption and wrapped data key
+func (e *EnvelopeEncryptor) encodeHeader() ([]byte, error) {
+	data, err := json.Marshal(e.Info())
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't encode header: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString(HEADER_MAGIC)
+	binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+	buf.Write(data)
+
+	return buf.Bytes(), nil
+}
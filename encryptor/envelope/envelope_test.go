@@ -0,0 +1,109 @@
+package envelope
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func TestHeader(t *testing.T) {
+	enc, err := NewEncryptor(&testProvider{})
+
+	if err != nil {
+		t.Fatalf("Can't create encryptor: %v", err)
+	}
+
+	var buf bytes.Buffer
+
+	w, err := enc.NewWriter(&buf)
+
+	if err != nil {
+		t.Fatalf("Can't create writer: %v", err)
+	}
+
+	w.Write([]byte("backup data"))
+	w.Close()
+
+	info, err := ReadHeader(bufio.NewReader(bytes.NewReader(buf.Bytes())))
+
+	if err != nil || info == nil {
+		t.Fatalf("Can't read header: %v", err)
+	}
+
+	if info.WrappedKey != enc.wrappedKey || info.Provider != "test" || info.KeyID != "test-key" {
+		t.Fatalf("Invalid header data %#v", info)
+	}
+
+	dec, err := NewDecryptor(&testProvider{}, info.WrappedKey)
+
+	if err != nil {
+		t.Fatalf("Can't create decryptor: %v", err)
+	}
+
+	r, err := dec.NewDecryptReader(bytes.NewReader(buf.Bytes()))
+
+	if err != nil {
+		t.Fatalf("Can't create decrypting reader: %v", err)
+	}
+
+	data, _ := io.ReadAll(r)
+
+	if !bytes.Contains(data, []byte("backup data")) || bytes.Contains(data, []byte(HEADER_MAGIC)) {
+		t.Fatal("Header is not skipped while decrypting")
+	}
+
+	// Reader must return the same header as writer
+	r, _ = enc.NewReader(strings.NewReader("backup data"))
+	info, err = ReadHeader(bufio.NewReader(r))
+
+	if err != nil || info == nil || info.WrappedKey != enc.wrappedKey {
+		t.Fatalf("Can't read header from encrypting reader: %v", err)
+	}
+}
+
+func TestHeaderMissing(t *testing.T) {
+	info, err := ReadHeader(bufio.NewReader(strings.NewReader("data without header")))
+
+	if err != nil || info != nil {
+		t.Fatal("Data without header must be ignored")
+	}
+
+	info, err = ReadHeader(bufio.NewReader(strings.NewReader(HEADER_MAGIC + "\xFF\xFF\xFF\xFF")))
+
+	if err == nil || info != nil {
+		t.Fatal("Header with invalid size must be rejected")
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// testProvider is KMS provider which only encodes data keys
+type testProvider struct{}
+
+func (p *testProvider) Name() string {
+	return "test"
+}
+
+func (p *testProvider) KeyID() string {
+	return "test-key"
+}
+
+func (p *testProvider) Wrap(key []byte) (string, error) {
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func (p *testProvider) Unwrap(wrappedKey string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(wrappedKey)
+}
@@ -0,0 +1,83 @@
+package katana
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/essentialkaos/katana"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// KatanaEncryptor is encryptor which uses katana symmetric encryption
+type KatanaEncryptor struct {
+	secret *katana.Secret
+	keyID  string
+	key    string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// validate encryptor interface
+var _ encryptor.Encryptor = (*KatanaEncryptor)(nil)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewEncryptor creates new katana encryptor instance for key with given ID. Key ID
+// can be empty for unnamed keys.
+func NewEncryptor(keyID, key string) (*KatanaEncryptor, error) {
+	if key == "" {
+		return nil, fmt.Errorf("Configuration validation error: encryption key is empty")
+	}
+
+	return &KatanaEncryptor{
+		secret: katana.NewSecret(key),
+		keyID:  keyID,
+		key:    key,
+	}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Info returns info about used encryption. Named keys are identified only by
+// ID, and for unnamed key fingerprint with random salt is generated.
+func (e *KatanaEncryptor) Info() *encryptor.Info {
+	info := &encryptor.Info{Type: "katana", KeyID: e.keyID}
+
+	if e.keyID != "" {
+		return info
+	}
+
+	salt := encryptor.NewSalt()
+	fingerprint, err := encryptor.Fingerprint(e.key, salt)
+
+	if err == nil {
+		info.Fingerprint, info.Salt = fingerprint, salt
+	}
+
+	return info
+}
+
+// NewWriter creates new writer which encrypts all data written to given writer
+func (e *KatanaEncryptor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return e.secret.NewWriter(w)
+}
+
+// NewReader creates new reader which encrypts all data read from given reader
+func (e *KatanaEncryptor) NewReader(r io.Reader) (io.Reader, error) {
+	return e.secret.NewReader(r, katana.MODE_ENCRYPT)
+}
+
+// NewDecryptReader creates new reader which decrypts all data read from given reader
+func (e *KatanaEncryptor) NewDecryptReader(r io.Reader) (io.Reader, error) {
+	return e.secret.NewReader(r, katana.MODE_DECRYPT)
+}
@@ -0,0 +1,324 @@
+package history
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/fsutil"
+	"github.com/essentialkaos/ek/v13/path"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	TRIGGER_CLI    = "cli"    // Run started from command line
+	TRIGGER_CRON   = "cron"   // Run started by built-in scheduler
+	TRIGGER_SERVER = "server" // Run started using server API
+)
+
+const (
+	OUTCOME_SUCCESS = "success"
+	OUTCOME_FAILURE = "failure"
+)
+
+// DB_FILE is name of history database file
+const DB_FILE = "runs.jsonl"
+
+// MAX_LINE_SIZE is max size of a single record in database file
+const MAX_LINE_SIZE = 1024 * 1024
+
+// MAX_RECORDS is max number of records kept in database
+const MAX_RECORDS = 10000
+
+// MAX_RECORD_AGE is max age of records kept in database
+const MAX_RECORD_AGE = 2 * 365 * 24 * time.Hour
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Record contains info about backup run
+type Record struct {
+	ID           string             `json:"id"`
+	Target       string             `json:"target"`
+	Trigger      string             `json:"trigger"`
+	JobType      string             `json:"job_type,omitempty"`
+	TaskID       string             `json:"task_id,omitempty"`
+	Outcome      string             `json:"outcome"`
+	Error        string             `json:"error,omitempty"`
+	Duration     float64            `json:"duration"`
+	Durations    map[string]float64 `json:"durations,omitempty"`
+	File         string             `json:"file,omitempty"`
+	Size         int64              `json:"size,omitempty"`
+	Checksum     string             `json:"checksum,omitempty"`
+	Destinations []string           `json:"destinations,omitempty"`
+	Started      time.Time          `json:"started"`
+	Finished     time.Time          `json:"finished"`
+}
+
+// Query contains records filter
+type Query struct {
+	Target  string
+	Trigger string
+	Outcome string
+	Since   time.Time
+	Limit   int
+}
+
+// Store is runs history store. Records are kept in append-only JSON Lines file,
+// so the store doesn't require any external database.
+type Store struct {
+	file       string
+	maxRecords int
+	maxAge     time.Duration
+	mu         *sync.Mutex
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewStore creates new history store in given directory
+func NewStore(dir string) (*Store, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("History directory is not set")
+	}
+
+	err := fsutil.ValidatePerms("DWX", dir)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't use directory for history: %w", err)
+	}
+
+	return &Store{
+		file:       path.Join(dir, DB_FILE),
+		maxRecords: MAX_RECORDS,
+		maxAge:     MAX_RECORD_AGE,
+		mu:         &sync.Mutex{},
+	}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Add adds record to history
+func (s *Store) Add(r *Record) error {
+	switch {
+	case s == nil:
+		return fmt.Errorf("History store is nil")
+	case r == nil:
+		return fmt.Errorf("Record is nil")
+	}
+
+	data, err := json.Marshal(r)
+
+	if err != nil {
+		return fmt.Errorf("Can't encode history record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err = s.prune()
+
+	if err != nil {
+		return fmt.Errorf("Can't prune history database: %w", err)
+	}
+
+	fd, err := os.OpenFile(s.file, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+
+	if err != nil {
+		return fmt.Errorf("Can't open history database: %w", err)
+	}
+
+	defer fd.Close()
+
+	// Last record may be partially written if process was killed, so new
+	// record must be started from the new line to keep it readable
+	if !isEndsWithNewLine(fd) {
+		data = append([]byte{'\n'}, data...)
+	}
+
+	_, err = fd.Write(append(data, '\n'))
+
+	if err != nil {
+		return fmt.Errorf("Can't write history record: %w", err)
+	}
+
+	return fd.Close()
+}
+
+// Find returns records matching given query sorted from newest to oldest
+func (s *Store) Find(q *Query) ([]*Record, error) {
+	if s == nil {
+		return nil, fmt.Errorf("History store is nil")
+	}
+
+	if q == nil {
+		q = &Query{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fd, err := os.Open(s.file)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Can't open history database: %w", err)
+	}
+
+	defer fd.Close()
+
+	var result []*Record
+
+	scanner := bufio.NewScanner(fd)
+	scanner.Buffer(make([]byte, 64*1024), MAX_LINE_SIZE)
+
+	for scanner.Scan() {
+		r := &Record{}
+
+		// Skip damaged records (e.g. partially written while process was killed)
+		if json.Unmarshal(scanner.Bytes(), r) != nil {
+			continue
+		}
+
+		if q.IsMatch(r) {
+			result = append(result, r)
+		}
+	}
+
+	err = scanner.Err()
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't read history database: %w", err)
+	}
+
+	slices.Reverse(result)
+
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// IsMatch returns true if record matches query
+func (q *Query) IsMatch(r *Record) bool {
+	switch {
+	case q.Target != "" && r.Target != q.Target,
+		q.Trigger != "" && r.Trigger != q.Trigger,
+		q.Outcome != "" && r.Outcome != q.Outcome,
+		!q.Since.IsZero() && r.Finished.Before(q.Since):
+		return false
+	}
+
+	return true
+}
+
+// IsSuccessful returns true if run was successful
+func (r *Record) IsSuccessful() bool {
+	return r != nil && r.Outcome == OUTCOME_SUCCESS
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// prune removes damaged records, records older than max age and the oldest
+// records exceeding max number of records. Database is rewritten only if
+// there is something to remove.
+func (s *Store) prune() error {
+	fd, err := os.Open(s.file)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	defer fd.Close()
+
+	var lines [][]byte
+	var hasGarbage bool
+
+	minDate := time.Now().Add(-s.maxAge)
+	scanner := bufio.NewScanner(fd)
+	scanner.Buffer(make([]byte, 64*1024), MAX_LINE_SIZE)
+
+	for scanner.Scan() {
+		r := &Record{}
+
+		if json.Unmarshal(scanner.Bytes(), r) != nil || r.Finished.Before(minDate) {
+			hasGarbage = true
+			continue
+		}
+
+		lines = append(lines, slices.Clone(scanner.Bytes()))
+	}
+
+	err = scanner.Err()
+
+	if err != nil {
+		return err
+	}
+
+	// One slot is reserved for the record which will be added
+	if len(lines) >= s.maxRecords {
+		lines, hasGarbage = lines[len(lines)-s.maxRecords+1:], true
+	}
+
+	if !hasGarbage {
+		return nil
+	}
+
+	var buf bytes.Buffer
+
+	for _, line := range lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	tmpFile := s.file + ".tmp"
+	err = os.WriteFile(tmpFile, buf.Bytes(), 0600)
+
+	if err == nil {
+		err = os.Rename(tmpFile, s.file)
+	}
+
+	if err != nil {
+		os.Remove(tmpFile)
+	}
+
+	return err
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// isEndsWithNewLine returns true if given file is empty or ends with new line
+// symbol
+func isEndsWithNewLine(fd *os.File) bool {
+	info, err := fd.Stat()
+
+	if err != nil || info.Size() == 0 {
+		return true
+	}
+
+	buf := make([]byte, 1)
+	_, err = fd.ReadAt(buf, info.Size()-1)
+
+	return err != nil || buf[0] == '\n'
+}
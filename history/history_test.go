@@ -0,0 +1,154 @@
+package history
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func TestAddFind(t *testing.T) {
+	s := createStore(t)
+
+	addRecords(t, s, time.Now(), "jira", "confluence", "jira")
+
+	records, err := s.Find(&Query{Target: "jira"})
+
+	if err != nil {
+		t.Fatalf("Can't find records: %v", err)
+	}
+
+	if len(records) != 2 || records[0].ID != "2" || records[1].ID != "0" {
+		t.Fatalf("Invalid records %v", records)
+	}
+}
+
+func TestAddAfterTruncatedRecord(t *testing.T) {
+	s := createStore(t)
+
+	addRecords(t, s, time.Now(), "jira")
+
+	// Emulate record partially written while process was killed
+	fd, _ := os.OpenFile(s.file, os.O_WRONLY|os.O_APPEND, 0600)
+	fd.WriteString(`{"id":"broken","target":"ji`)
+	fd.Close()
+
+	err := s.Add(&Record{ID: "new", Target: "jira", Finished: time.Now()})
+
+	if err != nil {
+		t.Fatalf("Can't add record: %v", err)
+	}
+
+	records, err := s.Find(nil)
+
+	if err != nil {
+		t.Fatalf("Can't find records: %v", err)
+	}
+
+	if len(records) != 2 || records[0].ID != "new" {
+		t.Fatalf("Record after truncated line must be readable: %v", records)
+	}
+}
+
+func TestEndsWithNewLine(t *testing.T) {
+	file := t.TempDir() + "/test.jsonl"
+
+	for data, expected := range map[string]bool{
+		"": true, "{}\n": true, "{}\n{": false,
+	} {
+		os.WriteFile(file, []byte(data), 0600)
+		fd, _ := os.Open(file)
+
+		if isEndsWithNewLine(fd) != expected {
+			t.Fatalf("Invalid result for %q", data)
+		}
+
+		fd.Close()
+	}
+}
+
+func TestPruneByNumber(t *testing.T) {
+	s := createStore(t)
+	s.maxRecords = 3
+
+	addRecords(t, s, time.Now(), "a", "b", "c", "d", "e")
+
+	records, _ := s.Find(nil)
+
+	if len(records) != 3 || records[0].Target != "e" || records[2].Target != "c" {
+		t.Fatalf("Invalid records after pruning %v", records)
+	}
+
+	data, _ := os.ReadFile(s.file)
+
+	if strings.Count(string(data), "\n") != 3 {
+		t.Fatalf("Database contains unexpected number of lines:\n%s", data)
+	}
+}
+
+func TestPruneByAge(t *testing.T) {
+	s := createStore(t)
+	s.maxAge = time.Hour
+
+	addRecords(t, s, time.Now().Add(-2*time.Hour), "old")
+	addRecords(t, s, time.Now(), "new")
+
+	records, _ := s.Find(nil)
+
+	if len(records) != 1 || records[0].Target != "new" {
+		t.Fatalf("Invalid records after pruning %v", records)
+	}
+}
+
+func TestNilStore(t *testing.T) {
+	var s *Store
+
+	if s.Add(&Record{}) == nil {
+		t.Fatal("Nil store must return error on Add")
+	}
+
+	if _, err := s.Find(nil); err == nil {
+		t.Fatal("Nil store must return error on Find")
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func createStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := NewStore(t.TempDir())
+
+	if err != nil {
+		t.Fatalf("Can't create store: %v", err)
+	}
+
+	return s
+}
+
+func addRecords(t *testing.T, s *Store, finished time.Time, targets ...string) {
+	t.Helper()
+
+	for i, target := range targets {
+		err := s.Add(&Record{
+			ID:       fmt.Sprintf("%d", i),
+			Target:   target,
+			Outcome:  OUTCOME_SUCCESS,
+			Finished: finished,
+		})
+
+		if err != nil {
+			t.Fatalf("Can't add record: %v", err)
+		}
+	}
+}
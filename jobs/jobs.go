@@ -0,0 +1,777 @@
+package jobs
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/fsutil"
+	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/path"
+	"github.com/essentialkaos/ek/v13/uuid"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	TYPE_CREATE   = "create"
+	TYPE_DOWNLOAD = "download"
+	TYPE_BACKUP   = "backup"
+)
+
+const (
+	PHASE_QUEUED      = "queued"
+	PHASE_CREATING    = "creating"
+	PHASE_DOWNLOADING = "downloading"
+	PHASE_UPLOADING   = "uploading"
+	PHASE_DONE        = "done"
+	PHASE_FAILED      = "failed"
+)
+
+// JOB_TTL is period of time while info about finished job is available
+const JOB_TTL = 24 * time.Hour
+
+const (
+	EVENT_STATE    = "state"
+	EVENT_PHASE    = "phase"
+	EVENT_FINISHED = "finished"
+)
+
+// EVENTS_BUFFER_SIZE is size of subscriber events buffer
+const EVENTS_BUFFER_SIZE = 32
+
+// ABORT_TIMEOUT is max time to wait for canceled jobs to finish
+const ABORT_TIMEOUT = 30 * time.Second
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrShutdown = errors.New("Jobs manager is shutting down")
+	ErrCanceled = errors.New("Job canceled")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Info contains info about job state
+type Info struct {
+	ID       string    `json:"id"`
+	Type     string    `json:"type"`
+	Target   string    `json:"target"`
+	Phase    string    `json:"phase"`
+	TaskID   string    `json:"task_id,omitempty"`
+	Message  string    `json:"message,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	Progress float64   `json:"progress"`
+	Duration float64   `json:"duration,omitempty"`
+	Bytes    int64     `json:"bytes"`
+	Total    int64     `json:"total,omitempty"`
+	Created  time.Time `json:"created"`
+	Started  time.Time `json:"started,omitzero"`
+	Finished time.Time `json:"finished,omitzero"`
+	Result   *Result   `json:"result,omitempty"`
+
+	// Durations contains durations of job phases in seconds
+	Durations map[string]float64 `json:"durations,omitempty"`
+}
+
+// Result contains info about uploaded backup
+type Result struct {
+	File     string `json:"file"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+	Location string `json:"location"`
+}
+
+// Event is job event
+type Event struct {
+	Type string
+	Data any
+}
+
+// Job is background backup job
+type Job struct {
+	info        Info
+	callback    string
+	interrupted bool
+	ctx         context.Context
+	done        chan struct{}
+	subscribers map[chan *Event]bool
+	stateFile   string
+	phaseStart  time.Time
+	mu          sync.RWMutex
+	saveMu      sync.Mutex
+}
+
+// state contains job data saved to state file
+type state struct {
+	Info
+
+	// Callback is URL for sending job result. It isn't a part of Info,
+	// because info is exposed via API.
+	Callback string `json:"callback,omitempty"`
+}
+
+// Handler is function which executes job
+type Handler func(job *Job) error
+
+// HandlerFactory is function which returns handler for restored job
+type HandlerFactory func(info Info) Handler
+
+// Config contains jobs manager configuration
+type Config struct {
+	// StateDir is directory for saving jobs state. If set, unfinished jobs
+	// can be restored after restart.
+	StateDir string
+
+	// OnStart is called when job is started
+	OnStart func(info Info)
+
+	// OnFinish is called when job is finished. It isn't called for jobs
+	// interrupted by shutdown which will be restored on the next start.
+	OnFinish func(info Info)
+}
+
+// Manager is background jobs manager
+type Manager struct {
+	config   Config
+	jobs     map[string]*Job
+	ctx      context.Context
+	cancel   context.CancelFunc
+	running  sync.WaitGroup
+	isClosed bool
+	mu       sync.RWMutex
+}
+
+// jobReader is reader which fails if job is canceled
+type jobReader struct {
+	r   io.Reader
+	ctx context.Context
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewManager creates new jobs manager
+func NewManager(config *Config) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m := &Manager{jobs: map[string]*Job{}, ctx: ctx, cancel: cancel}
+
+	if config != nil {
+		m.config = *config
+	}
+
+	return m
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Add creates new job and executes it in background
+func (m *Manager) Add(jobType, target string, handler Handler) (*Job, error) {
+	if m == nil {
+		return nil, fmt.Errorf("Jobs manager is nil")
+	}
+
+	if handler == nil {
+		return nil, fmt.Errorf("Job handler is nil")
+	}
+
+	id := uuid.UUID7().String()
+	job := &Job{
+		info: Info{
+			ID:      id,
+			Type:    jobType,
+			Target:  target,
+			Phase:   PHASE_QUEUED,
+			Created: time.Now().UTC(),
+		},
+		ctx:       m.ctx,
+		done:      make(chan struct{}),
+		stateFile: m.getStateFile(id),
+	}
+
+	m.mu.Lock()
+
+	if m.isClosed {
+		m.mu.Unlock()
+		return nil, ErrShutdown
+	}
+
+	m.prune()
+	m.jobs[job.info.ID] = job
+	m.running.Add(1)
+	m.mu.Unlock()
+
+	job.save()
+
+	go m.run(job, handler)
+
+	return job, nil
+}
+
+// Restore loads jobs from state directory and continues execution of
+// unfinished jobs. It returns number of restarted jobs.
+func (m *Manager) Restore(factory HandlerFactory) (int, error) {
+	if m == nil {
+		return 0, fmt.Errorf("Jobs manager is nil")
+	}
+
+	if m.config.StateDir == "" {
+		return 0, nil
+	}
+
+	if factory == nil {
+		return 0, fmt.Errorf("Handler factory is nil")
+	}
+
+	var restarted int
+
+	for _, file := range fsutil.List(m.config.StateDir, true, fsutil.ListingFilter{MatchPatterns: []string{"*.json"}}) {
+		stateFile := path.Join(m.config.StateDir, file)
+		st, err := readJobState(stateFile)
+
+		if err != nil {
+			log.Error("Can't restore job from %s: %v", stateFile, err)
+			continue
+		}
+
+		info := &st.Info
+		job := &Job{
+			info:      *info,
+			callback:  st.Callback,
+			ctx:       m.ctx,
+			done:      make(chan struct{}),
+			stateFile: stateFile,
+		}
+
+		if job.IsFinished() {
+			if time.Since(info.Finished) > JOB_TTL {
+				os.Remove(stateFile)
+				continue
+			}
+
+			close(job.done)
+
+			m.mu.Lock()
+			m.jobs[info.ID] = job
+			m.mu.Unlock()
+
+			continue
+		}
+
+		handler := factory(*info)
+
+		if handler == nil {
+			handler = func(_ *Job) error {
+				return fmt.Errorf("Can't restore job of type %q", info.Type)
+			}
+		}
+
+		m.mu.Lock()
+		m.jobs[info.ID] = job
+		m.running.Add(1)
+		m.mu.Unlock()
+
+		log.Info(
+			"Restoring unfinished job",
+			log.F{"job-id", info.ID}, log.F{"job-type", info.Type},
+			log.F{"phase", info.Phase}, log.F{"task-id", info.TaskID},
+		)
+
+		go m.run(job, handler)
+
+		restarted++
+	}
+
+	return restarted, nil
+}
+
+// Get returns job with given ID
+func (m *Manager) Get(id string) *Job {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.jobs[id]
+}
+
+// Shutdown stops accepting new jobs and waits until running jobs are finished.
+// If jobs are not finished within given timeout, they are canceled. Method
+// returns info about jobs which were not finished successfully.
+func (m *Manager) Shutdown(timeout time.Duration) []Info {
+	if m == nil {
+		return nil
+	}
+
+	var pending []*Job
+
+	m.mu.Lock()
+
+	m.isClosed = true
+
+	for _, job := range m.jobs {
+		if !job.IsFinished() {
+			pending = append(pending, job)
+		}
+	}
+
+	m.mu.Unlock()
+
+	if !m.wait(timeout) {
+		log.Warn("Grace period expired, canceling running jobs")
+		m.cancel()
+		m.wait(ABORT_TIMEOUT)
+	}
+
+	var result []Info
+
+	for _, job := range pending {
+		if job.Info().Phase != PHASE_DONE {
+			result = append(result, job.Info())
+		}
+	}
+
+	return result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ID returns job ID
+func (j *Job) ID() string {
+	if j == nil {
+		return ""
+	}
+
+	return j.info.ID
+}
+
+// Info returns copy of job state info
+func (j *Job) Info() Info {
+	if j == nil {
+		return Info{}
+	}
+
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return j.snapshot()
+}
+
+// IsFinished returns true if job is finished
+func (j *Job) IsFinished() bool {
+	info := j.Info()
+	return info.Phase == PHASE_DONE || info.Phase == PHASE_FAILED
+}
+
+// IsInterrupted returns true if job was canceled on shutdown and will be
+// restored on the next start
+func (j *Job) IsInterrupted() bool {
+	if j == nil {
+		return false
+	}
+
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return j.interrupted
+}
+
+// Wait blocks until job is finished and returns job error
+func (j *Job) Wait() error {
+	if j == nil {
+		return nil
+	}
+
+	<-j.done
+
+	if j.Info().Error != "" {
+		return errors.New(j.Info().Error)
+	}
+
+	return nil
+}
+
+// SetPhase sets current job phase and resets progress
+func (j *Job) SetPhase(phase string) {
+	if j == nil {
+		return
+	}
+
+	j.mu.Lock()
+	j.observePhase()
+	j.info.Phase, j.info.Message, j.info.Progress = phase, "", 0
+	j.phaseStart = time.Now()
+	j.mu.Unlock()
+
+	j.save()
+	j.Publish(EVENT_PHASE, j.Info())
+}
+
+// Callback returns URL for sending job result
+func (j *Job) Callback() string {
+	if j == nil {
+		return ""
+	}
+
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return j.callback
+}
+
+// SetCallback sets URL for sending job result. URL is saved with job state,
+// so it will be used after restoring the job.
+func (j *Job) SetCallback(url string) {
+	if j == nil {
+		return
+	}
+
+	j.mu.Lock()
+	j.callback = url
+	j.mu.Unlock()
+
+	j.save()
+}
+
+// SetTaskID sets ID of Atlassian backup task
+func (j *Job) SetTaskID(taskID string) {
+	if j == nil {
+		return
+	}
+
+	j.mu.Lock()
+	j.info.TaskID = taskID
+	j.mu.Unlock()
+
+	j.save()
+}
+
+// SetProgress sets job progress (0-100) with optional status message
+func (j *Job) SetProgress(progress float64, message string) {
+	if j == nil {
+		return
+	}
+
+	j.mu.Lock()
+	j.info.Progress = progress
+
+	if message != "" {
+		j.info.Message = message
+	}
+
+	j.mu.Unlock()
+}
+
+// SetBytes sets amount of transferred data and total data size
+func (j *Job) SetBytes(current, total int64) {
+	if j == nil {
+		return
+	}
+
+	j.mu.Lock()
+	j.info.Bytes, j.info.Total = current, total
+
+	if total > 0 {
+		j.info.Progress = float64(current) / float64(total) * 100
+	}
+
+	j.mu.Unlock()
+}
+
+// SetResult sets info about uploaded backup
+func (j *Job) SetResult(result *Result) {
+	if j == nil {
+		return
+	}
+
+	j.mu.Lock()
+	j.info.Result = result
+	j.mu.Unlock()
+
+	j.save()
+}
+
+// Subscribe returns channel with job events and function for unsubscribing.
+// The first event always contains current job state. Channel is closed when
+// job is finished.
+func (j *Job) Subscribe() (<-chan *Event, func()) {
+	ch := make(chan *Event, EVENTS_BUFFER_SIZE)
+
+	if j == nil {
+		close(ch)
+		return ch, func() {}
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.info.Phase == PHASE_DONE || j.info.Phase == PHASE_FAILED {
+		ch <- &Event{EVENT_FINISHED, j.snapshot()}
+		close(ch)
+		return ch, func() {}
+	}
+
+	ch <- &Event{EVENT_STATE, j.snapshot()}
+
+	if j.subscribers == nil {
+		j.subscribers = map[chan *Event]bool{}
+	}
+
+	j.subscribers[ch] = true
+
+	return ch, func() {
+		j.mu.Lock()
+		defer j.mu.Unlock()
+
+		if j.subscribers[ch] {
+			delete(j.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Publish sends event to all job subscribers. Events are dropped for subscribers
+// which don't read them fast enough.
+func (j *Job) Publish(eventType string, data any) {
+	if j == nil {
+		return
+	}
+
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	for ch := range j.subscribers {
+		select {
+		case ch <- &Event{eventType, data}:
+		default:
+		}
+	}
+}
+
+// Reader returns reader which fails with ErrCanceled if job is canceled
+func (j *Job) Reader(r io.Reader) io.Reader {
+	if j == nil || j.ctx == nil {
+		return r
+	}
+
+	return &jobReader{r: r, ctx: j.ctx}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Read reads data from underlying reader if job is not canceled
+func (r *jobReader) Read(p []byte) (int, error) {
+	if r.ctx.Err() != nil {
+		return 0, ErrCanceled
+	}
+
+	return r.r.Read(p)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// run executes job handler and updates job state
+func (m *Manager) run(job *Job, handler Handler) {
+	defer m.running.Done()
+
+	job.mu.Lock()
+	job.info.Started = time.Now().UTC()
+	job.mu.Unlock()
+
+	job.save()
+
+	if m.config.OnStart != nil {
+		m.config.OnStart(job.Info())
+	}
+
+	log.Info("Job started", log.F{"job-id", job.ID()}, log.F{"job-type", job.info.Type})
+
+	err := handler(job)
+
+	job.mu.Lock()
+
+	job.info.Finished = time.Now().UTC()
+	job.info.Duration = job.info.Finished.Sub(job.info.Started).Seconds()
+	job.observePhase()
+
+	if err != nil {
+		job.info.Phase, job.info.Error = PHASE_FAILED, err.Error()
+	} else {
+		job.info.Phase, job.info.Progress = PHASE_DONE, 100
+	}
+
+	for ch := range job.subscribers {
+		select {
+		case ch <- &Event{EVENT_FINISHED, job.snapshot()}:
+		default:
+		}
+
+		close(ch)
+	}
+
+	job.subscribers = nil
+
+	// State of jobs canceled on shutdown is kept unfinished, so they
+	// will be restored on the next start. Jobs which were finished or failed
+	// on their own during shutdown are saved as usual.
+	job.interrupted = job.stateFile != "" && m.ctx.Err() != nil && err != nil &&
+		(errors.Is(err, ErrCanceled) || errors.Is(err, context.Canceled))
+
+	job.mu.Unlock()
+
+	if !job.interrupted {
+		job.save()
+	}
+
+	close(job.done)
+
+	if job.interrupted {
+		log.Warn("Job interrupted by shutdown and will be restored on next start", log.F{"job-id", job.ID()})
+		return
+	}
+
+	if m.config.OnFinish != nil {
+		m.config.OnFinish(job.Info())
+	}
+
+	if err != nil {
+		log.Error("Job failed: %v", err, log.F{"job-id", job.ID()})
+	} else {
+		log.Info("Job successfully finished", log.F{"job-id", job.ID()})
+	}
+}
+
+// wait waits until all running jobs are finished and returns false if timeout
+// is reached
+func (m *Manager) wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+
+	go func() {
+		m.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// prune removes info about old finished jobs
+func (m *Manager) prune() {
+	for id, job := range m.jobs {
+		info := job.Info()
+
+		if !info.Finished.IsZero() && time.Since(info.Finished) > JOB_TTL {
+			delete(m.jobs, id)
+
+			if job.stateFile != "" {
+				os.Remove(job.stateFile)
+			}
+		}
+	}
+}
+
+// snapshot returns copy of job info (caller must hold lock)
+func (j *Job) snapshot() Info {
+	info := j.info
+	info.Durations = maps.Clone(j.info.Durations)
+
+	return info
+}
+
+// observePhase records duration of current phase
+func (j *Job) observePhase() {
+	if j.phaseStart.IsZero() {
+		return
+	}
+
+	if j.info.Durations == nil {
+		j.info.Durations = map[string]float64{}
+	}
+
+	j.info.Durations[j.info.Phase] += time.Since(j.phaseStart).Seconds()
+	j.phaseStart = time.Time{}
+}
+
+// getStateFile returns path to state file for job with given ID
+func (m *Manager) getStateFile(id string) string {
+	if m.config.StateDir == "" {
+		return ""
+	}
+
+	return path.Join(m.config.StateDir, id+".json")
+}
+
+// save atomically writes job state to state file
+func (j *Job) save() {
+	if j == nil || j.stateFile == "" {
+		return
+	}
+
+	j.saveMu.Lock()
+	defer j.saveMu.Unlock()
+
+	j.mu.RLock()
+	data, err := json.Marshal(&state{Info: j.snapshot(), Callback: j.callback})
+	j.mu.RUnlock()
+
+	if err != nil {
+		log.Error("Can't encode job state: %v", err, log.F{"job-id", j.ID()})
+		return
+	}
+
+	tmpFile := j.stateFile + ".tmp"
+	err = os.WriteFile(tmpFile, data, 0600)
+
+	if err == nil {
+		err = os.Rename(tmpFile, j.stateFile)
+	}
+
+	if err != nil {
+		os.Remove(tmpFile)
+		log.Error("Can't save job state: %v", err, log.F{"job-id", j.ID()})
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// readJobState reads job state from file
+func readJobState(file string) (*state, error) {
+	data, err := os.ReadFile(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	st := &state{}
+	err = json.Unmarshal(data, st)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if st.ID == "" || st.ID != strings.TrimSuffix(path.Base(file), ".json") {
+		return nil, fmt.Errorf("Job state file contains invalid job ID")
+	}
+
+	return st, nil
+}
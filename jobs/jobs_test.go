@@ -0,0 +1,183 @@
+package jobs
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func TestCallbackRestore(t *testing.T) {
+	dir := t.TempDir()
+
+	m := NewManager(&Config{StateDir: dir})
+	job, err := m.Add(TYPE_CREATE, "jira", func(job *Job) error {
+		<-job.ctx.Done()
+		return job.ctx.Err()
+	})
+
+	if err != nil {
+		t.Fatalf("Can't add job: %v", err)
+	}
+
+	job.SetCallback("https://example.com/callback")
+
+	data, _ := json.Marshal(job.Info())
+
+	if strings.Contains(string(data), "example.com") {
+		t.Fatalf("Callback URL is exposed via job info: %s", data)
+	}
+
+	// Emulate restart without finishing the job
+	st, err := readJobState(m.getStateFile(job.ID()))
+
+	if err != nil {
+		t.Fatalf("Can't read job state: %v", err)
+	}
+
+	if st.Callback != "https://example.com/callback" {
+		t.Fatalf("Callback URL is not saved with job state (%q)", st.Callback)
+	}
+
+	m.Shutdown(10 * time.Millisecond)
+
+	callbacks := make(chan string, 1)
+	rm := NewManager(&Config{StateDir: dir})
+
+	num, err := rm.Restore(func(info Info) Handler {
+		return func(job *Job) error {
+			callbacks <- job.Callback()
+			return nil
+		}
+	})
+
+	if err != nil || num != 1 {
+		t.Fatalf("Can't restore job (%d): %v", num, err)
+	}
+
+	select {
+	case url := <-callbacks:
+		if url != "https://example.com/callback" {
+			t.Fatalf("Invalid callback URL of restored job %q", url)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Restored job wasn't started")
+	}
+
+	rm.Shutdown(time.Second)
+}
+
+func TestShutdownInterrupt(t *testing.T) {
+	var finished []Info
+
+	dir := t.TempDir()
+	m := NewManager(&Config{
+		StateDir: dir,
+		OnFinish: func(info Info) { finished = append(finished, info) },
+	})
+
+	job, err := m.Add(TYPE_CREATE, "jira", func(job *Job) error {
+		<-job.ctx.Done()
+		return ErrCanceled
+	})
+
+	if err != nil {
+		t.Fatalf("Can't add job: %v", err)
+	}
+
+	unfinished := m.Shutdown(10 * time.Millisecond)
+
+	if len(unfinished) != 1 {
+		t.Fatalf("Invalid number of unfinished jobs %d", len(unfinished))
+	}
+
+	if !job.IsInterrupted() {
+		t.Fatal("Job must be marked as interrupted")
+	}
+
+	if len(finished) != 0 {
+		t.Fatalf("OnFinish must not be called for interrupted job: %v", finished)
+	}
+
+	st, err := readJobState(m.getStateFile(job.ID()))
+
+	if err != nil || st.Phase != PHASE_QUEUED {
+		t.Fatalf("Job state must be kept unfinished (%v): %v", st, err)
+	}
+}
+
+func TestShutdownFinished(t *testing.T) {
+	var finished []Info
+	var mu sync.Mutex
+
+	dir := t.TempDir()
+	m := NewManager(&Config{
+		StateDir: dir,
+		OnFinish: func(info Info) {
+			mu.Lock()
+			finished = append(finished, info)
+			mu.Unlock()
+		},
+	})
+
+	for _, jobErr := range []error{nil, errors.New("API error")} {
+		_, err := m.Add(TYPE_CREATE, "jira", func(job *Job) error {
+			<-job.ctx.Done()
+			return jobErr
+		})
+
+		if err != nil {
+			t.Fatalf("Can't add job: %v", err)
+		}
+	}
+
+	m.Shutdown(10 * time.Millisecond)
+
+	// Jobs finished on their own during shutdown must not be restored
+	if len(finished) != 2 {
+		t.Fatalf("OnFinish must be called for finished jobs: %v", finished)
+	}
+
+	num, err := NewManager(&Config{StateDir: dir}).Restore(func(info Info) Handler {
+		return func(job *Job) error { return nil }
+	})
+
+	if err != nil || num != 0 {
+		t.Fatalf("Finished jobs must not be restored (%d): %v", num, err)
+	}
+}
+
+func TestShutdownInterruptNoState(t *testing.T) {
+	var finished []Info
+
+	m := NewManager(&Config{
+		OnFinish: func(info Info) { finished = append(finished, info) },
+	})
+
+	job, err := m.Add(TYPE_CREATE, "jira", func(job *Job) error {
+		<-job.ctx.Done()
+		return ErrCanceled
+	})
+
+	if err != nil {
+		t.Fatalf("Can't add job: %v", err)
+	}
+
+	m.Shutdown(10 * time.Millisecond)
+
+	// Job can't be restored without state, so it must be reported as failed
+	if job.IsInterrupted() || len(finished) != 1 || finished[0].Phase != PHASE_FAILED {
+		t.Fatalf("Job must be reported as failed: %v", finished)
+	}
+}
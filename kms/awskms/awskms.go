@@ -0,0 +1,141 @@
+package awskms
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+
+	kmsp "github.com/essentialkaos/atlassian-cloud-backuper/kms"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Config is configuration for AWS KMS provider
+type Config struct {
+	Endpoint    string
+	Region      string
+	AccessKeyID string
+	SecretKey   string
+	KeyID       string
+}
+
+// AWSProvider is AWS KMS (or KMS-compatible API) provider
+type AWSProvider struct {
+	config *Config
+	client *kms.Client
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// validate provider interface
+var _ kmsp.Provider = (*AWSProvider)(nil)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewProvider creates new AWS KMS provider instance
+func NewProvider(config *Config) (*AWSProvider, error) {
+	err := config.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	opts := kms.Options{
+		Region: config.Region,
+		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(
+			config.AccessKeyID, config.SecretKey, "",
+		)),
+	}
+
+	if config.Endpoint != "" {
+		opts.BaseEndpoint = aws.String(config.Endpoint)
+	}
+
+	return &AWSProvider{config, kms.New(opts)}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Name returns provider name
+func (p *AWSProvider) Name() string {
+	return "aws"
+}
+
+// KeyID returns ID of the key used for wrapping data keys
+func (p *AWSProvider) KeyID() string {
+	return p.config.KeyID
+}
+
+// Wrap encrypts given data key
+func (p *AWSProvider) Wrap(key []byte) (string, error) {
+	resp, err := p.client.Encrypt(context.TODO(), &kms.EncryptInput{
+		KeyId:     aws.String(p.config.KeyID),
+		Plaintext: key,
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("Can't wrap data key: %v", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(resp.CiphertextBlob), nil
+}
+
+// Unwrap decrypts given wrapped data key
+func (p *AWSProvider) Unwrap(wrappedKey string) ([]byte, error) {
+	blob, err := base64.StdEncoding.DecodeString(wrappedKey)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't decode wrapped data key: %w", err)
+	}
+
+	resp, err := p.client.Decrypt(context.TODO(), &kms.DecryptInput{
+		KeyId:          aws.String(p.config.KeyID),
+		CiphertextBlob: blob,
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't unwrap data key: %v", err)
+	}
+
+	return resp.Plaintext, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Validate validates configuration
+func (c *Config) Validate() error {
+	switch {
+	case c == nil:
+		return fmt.Errorf("Configuration validation error: config is nil")
+
+	case c.Region == "":
+		return fmt.Errorf("Configuration validation error: region is empty")
+
+	case c.AccessKeyID == "":
+		return fmt.Errorf("Configuration validation error: access key is empty")
+
+	case c.SecretKey == "":
+		return fmt.Errorf("Configuration validation error: secret key is empty")
+
+	case c.KeyID == "":
+		return fmt.Errorf("Configuration validation error: key ID is empty")
+
+	case c.Endpoint != "" && !strings.HasPrefix(c.Endpoint, "https://") &&
+		!strings.HasPrefix(c.Endpoint, "http://"):
+		return fmt.Errorf("Configuration validation error: endpoint must contain scheme")
+	}
+
+	return nil
+}
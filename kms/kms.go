@@ -0,0 +1,23 @@
+package kms
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Provider is generic KMS provider interface
+type Provider interface {
+	// Name returns provider name
+	Name() string
+
+	// KeyID returns ID of the key used for wrapping data keys
+	KeyID() string
+
+	// Wrap encrypts given data key
+	Wrap(key []byte) (string, error)
+
+	// Unwrap decrypts given wrapped data key
+	Unwrap(wrappedKey string) ([]byte, error)
+}
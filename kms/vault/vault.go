@@ -0,0 +1,175 @@
+package vault
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/essentialkaos/ek/v13/req"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/kms"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Config is configuration for Vault Transit provider
+type Config struct {
+	Address string
+	Token   string
+	Mount   string
+	Key     string
+}
+
+// VaultProvider is HashiCorp Vault Transit KMS provider
+type VaultProvider struct {
+	config *Config
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+type encryptRequest struct {
+	Plaintext string `json:"plaintext"`
+}
+
+type decryptRequest struct {
+	Ciphertext string `json:"ciphertext"`
+}
+
+type transitResponse struct {
+	Data struct {
+		Ciphertext string `json:"ciphertext"`
+		Plaintext  string `json:"plaintext"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// validate provider interface
+var _ kms.Provider = (*VaultProvider)(nil)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewProvider creates new Vault Transit provider instance
+func NewProvider(config *Config) (*VaultProvider, error) {
+	err := config.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &VaultProvider{config}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Name returns provider name
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+// KeyID returns ID of the key used for wrapping data keys
+func (p *VaultProvider) KeyID() string {
+	return p.config.Mount + "/" + p.config.Key
+}
+
+// Wrap encrypts given data key
+func (p *VaultProvider) Wrap(key []byte) (string, error) {
+	resp, err := p.sendRequest("encrypt", &encryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(key),
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("Can't wrap data key: %w", err)
+	}
+
+	return resp.Data.Ciphertext, nil
+}
+
+// Unwrap decrypts given wrapped data key
+func (p *VaultProvider) Unwrap(wrappedKey string) ([]byte, error) {
+	resp, err := p.sendRequest("decrypt", &decryptRequest{Ciphertext: wrappedKey})
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't unwrap data key: %w", err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't decode data key: %w", err)
+	}
+
+	return key, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// sendRequest sends request to Transit secrets engine
+func (p *VaultProvider) sendRequest(action string, payload any) (*transitResponse, error) {
+	resp, err := req.Request{
+		URL: fmt.Sprintf(
+			"%s/v1/%s/%s/%s",
+			strings.TrimRight(p.config.Address, "/"), p.config.Mount, action, p.config.Key,
+		),
+		Headers:     req.Headers{"X-Vault-Token": p.config.Token},
+		Accept:      req.CONTENT_TYPE_JSON,
+		ContentType: req.CONTENT_TYPE_JSON,
+		Body:        payload,
+	}.Post()
+
+	if err != nil {
+		return nil, err
+	}
+
+	transitResp := &transitResponse{}
+	err = resp.JSON(transitResp)
+
+	if resp.StatusCode != 200 {
+		if len(transitResp.Errors) != 0 {
+			return nil, fmt.Errorf(
+				"Vault returned non-ok status code (%d): %s",
+				resp.StatusCode, strings.Join(transitResp.Errors, "; "),
+			)
+		}
+
+		return nil, fmt.Errorf("Vault returned non-ok status code (%d)", resp.StatusCode)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't decode Vault response: %v", err)
+	}
+
+	return transitResp, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Validate validates configuration
+func (c *Config) Validate() error {
+	switch {
+	case c == nil:
+		return fmt.Errorf("Configuration validation error: config is nil")
+
+	case c.Address == "":
+		return fmt.Errorf("Configuration validation error: address is empty")
+
+	case c.Token == "":
+		return fmt.Errorf("Configuration validation error: token is empty")
+
+	case c.Mount == "":
+		return fmt.Errorf("Configuration validation error: mount is empty")
+
+	case c.Key == "":
+		return fmt.Errorf("Configuration validation error: key is empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,346 @@
+package metrics
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	TYPE_GAUGE   = "gauge"
+	TYPE_COUNTER = "counter"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Registry is metrics registry
+type Registry struct {
+	metrics []*Metric
+	mu      sync.RWMutex
+}
+
+// Metric is metric with optional labels
+type Metric struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+	values map[string]*value
+	mu     sync.RWMutex
+}
+
+// value contains metric value for set of label values
+type value struct {
+	labels []string
+	value  float64
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewRegistry creates new metrics registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewGauge creates and registers new gauge metric
+func (r *Registry) NewGauge(name, help string, labels ...string) *Metric {
+	return r.add(name, help, TYPE_GAUGE, labels)
+}
+
+// NewCounter creates and registers new counter metric
+func (r *Registry) NewCounter(name, help string, labels ...string) *Metric {
+	return r.add(name, help, TYPE_COUNTER, labels)
+}
+
+// Encode encodes all metrics using Prometheus text exposition format
+func (r *Registry) Encode() []byte {
+	var buf bytes.Buffer
+
+	r.WriteTo(&buf)
+
+	return buf.Bytes()
+}
+
+// EncodeFor encodes only series with given label value using Prometheus text
+// exposition format. Metrics without given label are omitted.
+func (r *Registry) EncodeFor(label, labelValue string) []byte {
+	var buf bytes.Buffer
+
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, m := range r.metrics {
+		index := slices.Index(m.labels, label)
+
+		if index == -1 {
+			continue
+		}
+
+		buf.WriteString(m.encode(func(v *value) bool {
+			return v.labels[index] == labelValue
+		}))
+	}
+
+	return buf.Bytes()
+}
+
+// WriteTo writes all metrics to given writer using Prometheus text exposition format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+
+	if r == nil {
+		return 0, fmt.Errorf("Registry is nil")
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, m := range r.metrics {
+		n, err := io.WriteString(w, m.String())
+
+		total += int64(n)
+
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Set sets metric value for given label values
+func (m *Metric) Set(v float64, labels ...string) {
+	if m == nil || len(labels) != len(m.labels) {
+		return
+	}
+
+	m.mu.Lock()
+	m.get(labels).value = v
+	m.mu.Unlock()
+}
+
+// Add adds given delta to metric value for given label values
+func (m *Metric) Add(delta float64, labels ...string) {
+	if m == nil || len(labels) != len(m.labels) {
+		return
+	}
+
+	m.mu.Lock()
+	m.get(labels).value += delta
+	m.mu.Unlock()
+}
+
+// Inc increments metric value for given label values
+func (m *Metric) Inc(labels ...string) {
+	m.Add(1, labels...)
+}
+
+// Get returns metric value for given label values
+func (m *Metric) Get(labels ...string) float64 {
+	if m == nil {
+		return 0
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v := m.values[strings.Join(labels, "\x00")]
+
+	if v == nil {
+		return 0
+	}
+
+	return v.value
+}
+
+// Name returns metric name
+func (m *Metric) Name() string {
+	if m == nil {
+		return ""
+	}
+
+	return m.name
+}
+
+// Has returns true if metric has value for given label values
+func (m *Metric) Has(labels ...string) bool {
+	if m == nil {
+		return false
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.values[strings.Join(labels, "\x00")] != nil
+}
+
+// String returns metric in Prometheus text exposition format. Metrics without
+// values are omitted.
+func (m *Metric) String() string {
+	return m.encode(nil)
+}
+
+// Restore restores metric value for given label values from data encoded using
+// Prometheus text exposition format
+func (m *Metric) Restore(data []byte, labels ...string) bool {
+	if m == nil || len(labels) != len(m.labels) {
+		return false
+	}
+
+	prefix := m.series(labels) + " "
+
+	for line := range strings.Lines(string(data)) {
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(strings.TrimSpace(line[len(prefix):]), 64)
+
+		if err != nil {
+			return false
+		}
+
+		m.Set(v, labels...)
+
+		return true
+	}
+
+	return false
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// add registers new metric
+func (r *Registry) add(name, help, kind string, labels []string) *Metric {
+	m := &Metric{
+		name:   name,
+		help:   help,
+		kind:   kind,
+		labels: labels,
+		values: map[string]*value{},
+	}
+
+	if len(labels) == 0 {
+		m.values[""] = &value{}
+	}
+
+	r.mu.Lock()
+	r.metrics = append(r.metrics, m)
+	r.mu.Unlock()
+
+	return m
+}
+
+// encode returns metric series accepted by given filter in Prometheus text
+// exposition format. All series are returned if filter is nil.
+func (m *Metric) encode(filter func(v *value) bool) string {
+	if m == nil {
+		return ""
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]string, 0, len(m.values))
+
+	for k, v := range m.values {
+		if filter == nil || filter(v) {
+			keys = append(keys, k)
+		}
+	}
+
+	if len(keys) == 0 {
+		return ""
+	}
+
+	slices.Sort(keys)
+
+	var buf strings.Builder
+
+	fmt.Fprintf(&buf, "# HELP %s %s\n", m.name, escapeHelp(m.help))
+	fmt.Fprintf(&buf, "# TYPE %s %s\n", m.name, m.kind)
+
+	for _, k := range keys {
+		v := m.values[k]
+
+		buf.WriteString(m.series(v.labels))
+		buf.WriteRune(' ')
+		buf.WriteString(strconv.FormatFloat(v.value, 'g', -1, 64))
+		buf.WriteRune('\n')
+	}
+
+	return buf.String()
+}
+
+// series returns series name with given label values
+func (m *Metric) series(labels []string) string {
+	if len(m.labels) == 0 {
+		return m.name
+	}
+
+	var buf strings.Builder
+
+	buf.WriteString(m.name)
+	buf.WriteRune('{')
+
+	for i, l := range m.labels {
+		if i > 0 {
+			buf.WriteRune(',')
+		}
+
+		fmt.Fprintf(&buf, `%s="%s"`, l, escapeLabel(labels[i]))
+	}
+
+	buf.WriteRune('}')
+
+	return buf.String()
+}
+
+// get returns value struct for given label values
+func (m *Metric) get(labels []string) *value {
+	key := strings.Join(labels, "\x00")
+	v := m.values[key]
+
+	if v == nil {
+		v = &value{labels: slices.Clone(labels)}
+		m.values[key] = v
+	}
+
+	return v
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// escapeHelp escapes help text
+func escapeHelp(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	return strings.ReplaceAll(text, "\n", `\n`)
+}
+
+// escapeLabel escapes label value
+func escapeLabel(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, `"`, `\"`)
+	return strings.ReplaceAll(text, "\n", `\n`)
+}
@@ -0,0 +1,54 @@
+package metrics
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"strings"
+	"testing"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func TestEncodeFor(t *testing.T) {
+	r := NewRegistry()
+
+	success := r.NewGauge("last_success", "Time of the last success", "target")
+	errs := r.NewCounter("api_errors_total", "Number of API errors", "target", "code")
+	r.NewGauge("uptime", "Uptime")
+
+	success.Set(100, "jira")
+	success.Set(200, "confluence")
+	errs.Inc("confluence", "500")
+
+	data := string(r.EncodeFor("target", "jira"))
+
+	if !strings.Contains(data, `last_success{target="jira"} 100`) {
+		t.Fatalf("Series for target is not encoded:\n%s", data)
+	}
+
+	if strings.Contains(data, "confluence") || strings.Contains(data, "api_errors_total") {
+		t.Fatalf("Series for other targets must be omitted:\n%s", data)
+	}
+
+	if strings.Contains(data, "uptime") {
+		t.Fatalf("Metrics without label must be omitted:\n%s", data)
+	}
+
+	data = string(r.EncodeFor("target", "confluence"))
+
+	if !strings.Contains(data, `api_errors_total{target="confluence",code="500"} 1`) {
+		t.Fatalf("Series for target is not encoded:\n%s", data)
+	}
+
+	data = string(r.Encode())
+
+	if !strings.Contains(data, "jira") || !strings.Contains(data, "confluence") ||
+		!strings.Contains(data, "uptime 0") {
+		t.Fatalf("All series must be encoded:\n%s", data)
+	}
+}
@@ -0,0 +1,603 @@
+package chat
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/fmtutil"
+	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/req"
+	"github.com/essentialkaos/ek/v13/timeutil"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/notifier"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	TYPE_SLACK      = "slack"
+	TYPE_MATTERMOST = "mattermost"
+)
+
+const (
+	COLOR_GOOD    = "#2eb886"
+	COLOR_DANGER  = "#d50200"
+	COLOR_WARNING = "#daa038"
+	COLOR_INFO    = "#439fe0"
+)
+
+const (
+	// DEFAULT_TIMEOUT is default request timeout
+	DEFAULT_TIMEOUT = 30 * time.Second
+
+	// SLACK_API_URL is URL of Slack chat.postMessage API method
+	SLACK_API_URL = "https://slack.com/api/chat.postMessage"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Config is configuration for chat notifier
+type Config struct {
+	Type    string // slack or mattermost
+	Webhook string // Incoming webhook URL
+
+	// Posting messages using API is required for threaded follow-ups, because
+	// incoming webhooks don't return ID of created message
+	APIURL  string // Mattermost server URL
+	Token   string // Slack bot token or Mattermost access token
+	Channel string // Slack channel or Mattermost channel ID
+
+	Link     string // Template of link to backup in storage
+	Digest   bool   // Send successful backups as daily digest
+	StateDir string // Directory for notifier state
+	Timeout  time.Duration
+}
+
+// ChatNotifier sends messages to Slack or Mattermost
+type ChatNotifier struct {
+	config *Config
+	link   *template.Template
+	state  *State
+	mu     *sync.Mutex
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Message is chat message
+type Message struct {
+	Text        string        `json:"text,omitempty"`
+	Attachments []*Attachment `json:"attachments,omitempty"`
+	Thread      string        `json:"-"`
+}
+
+// Attachment is message attachment (supported by both Slack and Mattermost)
+type Attachment struct {
+	Fallback  string   `json:"fallback,omitempty"`
+	Color     string   `json:"color,omitempty"`
+	Title     string   `json:"title,omitempty"`
+	TitleLink string   `json:"title_link,omitempty"`
+	Text      string   `json:"text,omitempty"`
+	Fields    []*Field `json:"fields,omitempty"`
+	Footer    string   `json:"footer,omitempty"`
+	Timestamp int64    `json:"ts,omitempty"`
+}
+
+// Field is attachment field
+type Field struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// validate default interface implementation
+var _ notifier.Notifier = (*ChatNotifier)(nil)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewNotifier creates new chat notifier instance
+func NewNotifier(config *Config) (*ChatNotifier, error) {
+	err := config.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = DEFAULT_TIMEOUT
+	}
+
+	n := &ChatNotifier{
+		config: config,
+		state:  loadState(config.StateDir, "chat-"+config.Type),
+		mu:     &sync.Mutex{},
+	}
+
+	if config.Link != "" {
+		n.link, err = template.New("link").Parse(config.Link)
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't parse link template: %w", err)
+		}
+	}
+
+	return n, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Name returns notifier name
+func (n *ChatNotifier) Name() string {
+	return n.config.Type
+}
+
+// Send sends notification to chat
+func (n *ChatNotifier) Send(nt *notifier.Notification) error {
+	if n == nil {
+		return fmt.Errorf("Notifier is nil")
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	defer n.state.Save()
+
+	var err error
+
+	switch nt.Event {
+	case notifier.EVENT_FAILURE:
+		err = n.sendFailure(nt)
+	case notifier.EVENT_SUCCESS:
+		err = n.sendSuccess(nt)
+	default:
+		_, err = n.post(n.getEventMessage(nt))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return n.sendDigest(false)
+}
+
+// SendDigest sends digest with info about successful backups if digest is due.
+// If force is true, digest is sent even if it's not due.
+func (n *ChatNotifier) SendDigest(force bool) error {
+	if n == nil {
+		return fmt.Errorf("Notifier is nil")
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	defer n.state.Save()
+
+	return n.sendDigest(force)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Validate validates configuration
+func (c *Config) Validate() error {
+	switch {
+	case c == nil:
+		return fmt.Errorf("Configuration validation error: config is nil")
+	case c.Type != TYPE_SLACK && c.Type != TYPE_MATTERMOST:
+		return fmt.Errorf("Configuration validation error: unknown chat type %q", c.Type)
+	case c.Webhook == "" && c.Token == "":
+		return fmt.Errorf("Configuration validation error: webhook URL or token must be set")
+	case c.Token != "" && c.Channel == "":
+		return fmt.Errorf("Configuration validation error: channel is empty")
+	case c.Token != "" && c.Type == TYPE_MATTERMOST && c.APIURL == "":
+		return fmt.Errorf("Configuration validation error: Mattermost server URL is empty")
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// sendFailure sends message about failed backup. All failures of the same target
+// are posted to the thread of the first failure.
+func (n *ChatNotifier) sendFailure(nt *notifier.Notification) error {
+	msg := n.getEventMessage(nt)
+	failure := n.state.Failures[nt.Target]
+
+	if failure != nil {
+		msg.Thread = failure.Thread
+	}
+
+	thread, err := n.post(msg)
+
+	if err != nil {
+		return err
+	}
+
+	if failure == nil {
+		n.state.Failures[nt.Target] = &Failure{
+			Thread: thread,
+			Error:  nt.Error,
+			Time:   nt.Time,
+		}
+	}
+
+	return nil
+}
+
+// sendSuccess sends message about successful backup or adds it to digest
+func (n *ChatNotifier) sendSuccess(nt *notifier.Notification) error {
+	trend := n.getSizeTrend(nt.Target, nt.Size)
+
+	if nt.Size > 0 {
+		n.state.Sizes[nt.Target] = nt.Size
+	}
+
+	failure := n.state.Failures[nt.Target]
+
+	if failure != nil {
+		_, err := n.post(n.getRecoveryMessage(nt, failure))
+
+		if err != nil {
+			return err
+		}
+
+		delete(n.state.Failures, nt.Target)
+	}
+
+	if !n.config.Digest {
+		msg := n.getEventMessage(nt)
+		msg.Attachments[0].Fields = append(msg.Attachments[0].Fields, &Field{"Size trend", trend, true})
+		_, err := n.post(msg)
+		return err
+	}
+
+	n.state.Digest = append(n.state.Digest, &DigestRecord{
+		Target:   nt.Target,
+		File:     nt.File,
+		Size:     nt.Size,
+		Trend:    trend,
+		Duration: nt.Duration,
+		Location: nt.Location,
+		Link:     n.getLink(nt),
+		Time:     nt.Time,
+	})
+
+	return nil
+}
+
+// sendDigest sends digest with info about successful backups
+func (n *ChatNotifier) sendDigest(force bool) error {
+	if len(n.state.Digest) == 0 || (!force && !n.state.IsDigestDue(time.Now())) {
+		return nil
+	}
+
+	_, err := n.post(n.getDigestMessage(n.state.Digest))
+
+	if err != nil {
+		return fmt.Errorf("Can't send digest: %w", err)
+	}
+
+	n.state.Digest = nil
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getEventMessage creates message for given notification
+func (n *ChatNotifier) getEventMessage(nt *notifier.Notification) *Message {
+	var title, color string
+
+	switch nt.Event {
+	case notifier.EVENT_START:
+		title, color = fmt.Sprintf("Backup of %s started", nt.Target), COLOR_INFO
+	case notifier.EVENT_SUCCESS:
+		title, color = fmt.Sprintf("Backup of %s successfully created", nt.Target), COLOR_GOOD
+	case notifier.EVENT_FAILURE:
+		title, color = fmt.Sprintf("Backup of %s failed", nt.Target), COLOR_DANGER
+	default:
+		title, color = fmt.Sprintf("Backup of %s: %s", nt.Target, nt.Event), COLOR_WARNING
+	}
+
+	att := &Attachment{
+		Fallback:  title,
+		Color:     color,
+		Title:     title,
+		TitleLink: n.getLink(nt),
+		Text:      nt.Message,
+		Footer:    "Atlassian Cloud Backuper",
+		Timestamp: nt.Time.Unix(),
+	}
+
+	if nt.Error != "" {
+		att.Text = "```\n" + nt.Error + "\n```"
+	}
+
+	att.Fields = append(att.Fields, &Field{"Target", nt.Target, true})
+
+	if nt.Account != "" {
+		att.Fields = append(att.Fields, &Field{"Account", nt.Account, true})
+	}
+
+	if nt.Duration > 0 {
+		att.Fields = append(att.Fields, &Field{"Duration", formatDuration(nt.Duration), true})
+	}
+
+	if nt.Size > 0 {
+		att.Fields = append(att.Fields, &Field{"Size", fmtutil.PrettySize(nt.Size), true})
+	}
+
+	if nt.Location != "" {
+		att.Fields = append(att.Fields, &Field{"Location", "`" + nt.Location + "`", false})
+	}
+
+	return &Message{Attachments: []*Attachment{att}}
+}
+
+// getRecoveryMessage creates follow-up message for recovered target
+func (n *ChatNotifier) getRecoveryMessage(nt *notifier.Notification, failure *Failure) *Message {
+	title := fmt.Sprintf("Backup of %s recovered", nt.Target)
+
+	return &Message{
+		Thread: failure.Thread,
+		Attachments: []*Attachment{{
+			Fallback:  title,
+			Color:     COLOR_GOOD,
+			Title:     title,
+			TitleLink: n.getLink(nt),
+			Text: fmt.Sprintf(
+				"Backup successfully created (was failing for %s)",
+				formatDuration(nt.Time.Sub(failure.Time).Seconds()),
+			),
+			Footer:    "Atlassian Cloud Backuper",
+			Timestamp: nt.Time.Unix(),
+		}},
+	}
+}
+
+// getDigestMessage creates digest message
+func (n *ChatNotifier) getDigestMessage(records []*DigestRecord) *Message {
+	var buf strings.Builder
+
+	title := fmt.Sprintf("Backup digest: %d successful backup(s)", len(records))
+
+	for _, r := range records {
+		target := "`" + r.Target + "`"
+
+		if r.Link != "" {
+			target = n.formatLink(r.Link, r.Target)
+		}
+
+		fmt.Fprintf(
+			&buf, "• %s %s — %s (%s) in %s\n",
+			r.Time.Local().Format("2006-01-02 15:04"), target,
+			fmtutil.PrettySize(r.Size), r.Trend, formatDuration(r.Duration),
+		)
+	}
+
+	return &Message{
+		Attachments: []*Attachment{{
+			Fallback:  title,
+			Color:     COLOR_GOOD,
+			Title:     title,
+			Text:      buf.String(),
+			Footer:    "Atlassian Cloud Backuper",
+			Timestamp: time.Now().Unix(),
+		}},
+	}
+}
+
+// getSizeTrend returns size difference with previous backup of the same target
+func (n *ChatNotifier) getSizeTrend(target string, size int64) string {
+	prevSize, ok := n.state.Sizes[target]
+
+	switch {
+	case size <= 0:
+		return "unknown"
+	case !ok || prevSize <= 0:
+		return "no previous backup"
+	case size == prevSize:
+		return "no changes"
+	}
+
+	diff := size - prevSize
+	perc := float64(diff) / float64(prevSize) * 100
+	sign := "+"
+
+	if diff < 0 {
+		sign = "-"
+	}
+
+	return fmt.Sprintf(
+		"%s%s, %s%.1f%%", sign, fmtutil.PrettySize(abs(diff)), sign, math.Abs(perc),
+	)
+}
+
+// getLink returns link to backup in storage
+func (n *ChatNotifier) getLink(nt *notifier.Notification) string {
+	if nt.Location == "" {
+		return ""
+	}
+
+	if n.link == nil {
+		if strings.HasPrefix(nt.Location, "http://") || strings.HasPrefix(nt.Location, "https://") {
+			return nt.Location
+		}
+
+		return ""
+	}
+
+	var buf bytes.Buffer
+
+	err := n.link.Execute(&buf, nt)
+
+	if err != nil {
+		log.Error("Can't render chat link template: %v", err)
+		return ""
+	}
+
+	return strings.TrimSpace(buf.String())
+}
+
+// formatLink formats link using chat markup
+func (n *ChatNotifier) formatLink(url, text string) string {
+	if n.config.Type == TYPE_SLACK {
+		return "<" + url + "|" + text + ">"
+	}
+
+	return "[" + text + "](" + url + ")"
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// post posts message and returns ID of created message if it's available
+func (n *ChatNotifier) post(msg *Message) (string, error) {
+	switch {
+	case n.config.Token == "":
+		return "", n.postWebhook(msg)
+	case n.config.Type == TYPE_SLACK:
+		return n.postSlack(msg)
+	}
+
+	return n.postMattermost(msg)
+}
+
+// postWebhook posts message using incoming webhook
+func (n *ChatNotifier) postWebhook(msg *Message) error {
+	resp, err := req.Request{
+		URL:         n.config.Webhook,
+		ContentType: req.CONTENT_TYPE_JSON,
+		Body:        msg,
+		Timeout:     n.config.Timeout,
+		AutoDiscard: true,
+	}.Post()
+
+	if err != nil {
+		return fmt.Errorf("Can't send request: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Webhook returned non-ok status code (%d)", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// postSlack posts message using Slack API
+func (n *ChatNotifier) postSlack(msg *Message) (string, error) {
+	body := map[string]any{
+		"channel":     n.config.Channel,
+		"text":        msg.Text,
+		"attachments": msg.Attachments,
+	}
+
+	if msg.Thread != "" {
+		body["thread_ts"] = msg.Thread
+	}
+
+	resp, err := req.Request{
+		URL:         SLACK_API_URL,
+		ContentType: req.CONTENT_TYPE_JSON,
+		Auth:        req.AuthBearer{n.config.Token},
+		Body:        body,
+		Timeout:     n.config.Timeout,
+	}.Post()
+
+	if err != nil {
+		return "", fmt.Errorf("Can't send request: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		resp.Discard()
+		return "", fmt.Errorf("Slack API returned non-ok status code (%d)", resp.StatusCode)
+	}
+
+	result := &struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		TS    string `json:"ts"`
+	}{}
+
+	err = resp.JSON(result)
+
+	if err != nil {
+		return "", fmt.Errorf("Can't decode Slack API response: %w", err)
+	}
+
+	if !result.OK {
+		return "", fmt.Errorf("Slack API returned error: %s", result.Error)
+	}
+
+	return result.TS, nil
+}
+
+// postMattermost posts message using Mattermost API
+func (n *ChatNotifier) postMattermost(msg *Message) (string, error) {
+	body := map[string]any{
+		"channel_id": n.config.Channel,
+		"message":    msg.Text,
+		"props":      map[string]any{"attachments": msg.Attachments},
+	}
+
+	if msg.Thread != "" {
+		body["root_id"] = msg.Thread
+	}
+
+	resp, err := req.Request{
+		URL:         strings.TrimRight(n.config.APIURL, "/") + "/api/v4/posts",
+		ContentType: req.CONTENT_TYPE_JSON,
+		Auth:        req.AuthBearer{n.config.Token},
+		Body:        body,
+		Timeout:     n.config.Timeout,
+	}.Post()
+
+	if err != nil {
+		return "", fmt.Errorf("Can't send request: %w", err)
+	}
+
+	if resp.StatusCode != 201 {
+		resp.Discard()
+		return "", fmt.Errorf("Mattermost API returned non-ok status code (%d)", resp.StatusCode)
+	}
+
+	result := &struct {
+		ID string `json:"id"`
+	}{}
+
+	err = resp.JSON(result)
+
+	if err != nil {
+		return "", fmt.Errorf("Can't decode Mattermost API response: %w", err)
+	}
+
+	return result.ID, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// formatDuration formats duration in seconds
+func formatDuration(v float64) string {
+	return timeutil.ShortDuration(time.Duration(v * float64(time.Second)))
+}
+
+// abs returns absolute value of given number
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
@@ -0,0 +1,99 @@
+package chat
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"os"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/fsutil"
+	"github.com/essentialkaos/ek/v13/jsonutil"
+	"github.com/essentialkaos/ek/v13/path"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// State contains info required for size trends, digests and follow-ups, which
+// must survive between runs
+type State struct {
+	Sizes    map[string]int64    `json:"sizes,omitempty"`
+	Failures map[string]*Failure `json:"failures,omitempty"`
+	Digest   []*DigestRecord     `json:"digest,omitempty"`
+
+	file string
+}
+
+// Failure contains info about unresolved backup failure
+type Failure struct {
+	Thread string    `json:"thread,omitempty"`
+	Error  string    `json:"error"`
+	Time   time.Time `json:"time"`
+}
+
+// DigestRecord contains info about successful backup waiting for digest
+type DigestRecord struct {
+	Target   string    `json:"target"`
+	File     string    `json:"file,omitempty"`
+	Size     int64     `json:"size,omitempty"`
+	Trend    string    `json:"trend,omitempty"`
+	Duration float64   `json:"duration"`
+	Location string    `json:"location,omitempty"`
+	Link     string    `json:"link,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// loadState loads state from file in given directory. If directory is empty,
+// state is kept in memory only.
+func loadState(dir, name string) *State {
+	state := &State{}
+
+	if dir != "" {
+		state.file = path.Join(dir, name+".json")
+
+		if fsutil.IsExist(state.file) && jsonutil.Read(state.file, state) != nil {
+			os.Remove(state.file)
+			state = &State{file: state.file}
+		}
+	}
+
+	if state.Sizes == nil {
+		state.Sizes = map[string]int64{}
+	}
+
+	if state.Failures == nil {
+		state.Failures = map[string]*Failure{}
+	}
+
+	return state
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Save saves state to file
+func (s *State) Save() error {
+	if s == nil || s.file == "" {
+		return nil
+	}
+
+	return jsonutil.Write(s.file, s, 0600)
+}
+
+// IsDigestDue returns true if there are digest records created before the
+// start of current day
+func (s *State) IsDigestDue(now time.Time) bool {
+	if s == nil || len(s.Digest) == 0 {
+		return false
+	}
+
+	y1, m1, d1 := s.Digest[0].Time.Local().Date()
+	y2, m2, d2 := now.Local().Date()
+
+	return y1 != y2 || m1 != m2 || d1 != d2
+}
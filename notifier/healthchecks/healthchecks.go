@@ -0,0 +1,234 @@
+package healthchecks
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/fmtutil"
+	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/req"
+	"github.com/essentialkaos/ek/v13/timeutil"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/notifier"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	// DEFAULT_TIMEOUT is default request timeout
+	DEFAULT_TIMEOUT = 10 * time.Second
+
+	// MAX_ATTEMPTS is max number of ping attempts
+	MAX_ATTEMPTS = 3
+
+	// MAX_BODY_SIZE is max size of ping body accepted by healthchecks.io
+	MAX_BODY_SIZE = 100 * 1024
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Config is configuration for healthchecks.io notifier
+type Config struct {
+	URL     string // Ping URL (e.g. https://hc-ping.com/<uuid>)
+	Timeout time.Duration
+}
+
+// HealthchecksNotifier sends pings to healthchecks.io compatible services
+type HealthchecksNotifier struct {
+	config *Config
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// validate default interface implementation
+var _ notifier.Notifier = (*HealthchecksNotifier)(nil)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// endpoints contains ping endpoints for every event
+var endpoints = map[string]string{
+	notifier.EVENT_START:   "/start",
+	notifier.EVENT_SUCCESS: "",
+	notifier.EVENT_FAILURE: "/fail",
+	notifier.EVENT_WARNING: "/log",
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewNotifier creates new healthchecks.io notifier instance
+func NewNotifier(config *Config) (*HealthchecksNotifier, error) {
+	err := config.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	config.URL = strings.TrimRight(config.URL, "/")
+
+	if config.Timeout <= 0 {
+		config.Timeout = DEFAULT_TIMEOUT
+	}
+
+	return &HealthchecksNotifier{config}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Name returns notifier name
+func (n *HealthchecksNotifier) Name() string {
+	return "healthchecks"
+}
+
+// Send sends ping with info about backup
+func (n *HealthchecksNotifier) Send(nt *notifier.Notification) error {
+	if n == nil {
+		return fmt.Errorf("Notifier is nil")
+	}
+
+	endpoint, ok := endpoints[nt.Event]
+
+	if !ok {
+		return fmt.Errorf("Unsupported event %q", nt.Event)
+	}
+
+	r := req.Request{
+		URL:         n.config.URL + endpoint,
+		ContentType: req.CONTENT_TYPE_PLAIN,
+		Body:        getPingBody(nt),
+		Timeout:     n.config.Timeout,
+		AutoDiscard: true,
+	}
+
+	// Run ID allows service to match start and finish pings of the same run
+	// and calculate run duration even if there are several concurrent runs
+	if nt.Job != "" {
+		r.Query = req.Query{"rid": nt.Job}
+	}
+
+	var err error
+
+	for attempt := 1; attempt <= MAX_ATTEMPTS; attempt++ {
+		err = ping(r)
+
+		if err == nil {
+			log.Debug("Healthchecks ping sent", log.F{"event", nt.Event})
+			return nil
+		}
+
+		if attempt < MAX_ATTEMPTS {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+	}
+
+	return err
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Validate validates configuration
+func (c *Config) Validate() error {
+	switch {
+	case c == nil:
+		return fmt.Errorf("Configuration validation error: config is nil")
+	case c.URL == "":
+		return fmt.Errorf("Configuration validation error: ping URL is empty")
+	}
+
+	u, err := url.Parse(c.URL)
+
+	if err != nil || !slices.Contains([]string{"http", "https"}, u.Scheme) || u.Host == "" {
+		return fmt.Errorf("Configuration validation error: invalid ping URL %q", c.URL)
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ping sends ping request
+func ping(r req.Request) error {
+	resp, err := r.Post()
+
+	if err != nil {
+		return fmt.Errorf("Can't send ping: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Ping endpoint returned non-ok status code (%d)", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// getPingBody returns body of ping with info about backup
+func getPingBody(nt *notifier.Notification) string {
+	var buf strings.Builder
+
+	fmt.Fprintf(&buf, "Target: %s\n", nt.Target)
+
+	if nt.Account != "" {
+		fmt.Fprintf(&buf, "Account: %s\n", nt.Account)
+	}
+
+	if nt.Job != "" {
+		fmt.Fprintf(&buf, "Job: %s (%s)\n", nt.Job, nt.JobType)
+	}
+
+	if nt.Message != "" {
+		fmt.Fprintf(&buf, "Message: %s\n", nt.Message)
+	}
+
+	if nt.Duration > 0 {
+		fmt.Fprintf(&buf, "Duration: %s\n", formatDuration(nt.Duration))
+	}
+
+	for _, phase := range slices.Sorted(maps.Keys(nt.Durations)) {
+		fmt.Fprintf(&buf, "Duration (%s): %s\n", phase, formatDuration(nt.Durations[phase]))
+	}
+
+	if nt.File != "" {
+		fmt.Fprintf(&buf, "File: %s\n", nt.File)
+		fmt.Fprintf(&buf, "Size: %s\n", fmtutil.PrettySize(nt.Size))
+	}
+
+	if nt.Checksum != "" {
+		fmt.Fprintf(&buf, "Checksum: %s\n", nt.Checksum)
+	}
+
+	if nt.Location != "" {
+		fmt.Fprintf(&buf, "Location: %s\n", nt.Location)
+	}
+
+	if nt.Error != "" {
+		fmt.Fprintf(&buf, "Error: %s\n", nt.Error)
+	}
+
+	if len(nt.Logs) != 0 {
+		fmt.Fprintf(&buf, "\nLog:\n%s\n", strings.Join(nt.Logs, "\n"))
+	}
+
+	body := buf.String()
+
+	if len(body) > MAX_BODY_SIZE {
+		// Keep the tail, because the latest log records are the most important
+		body = body[len(body)-MAX_BODY_SIZE:]
+	}
+
+	return body
+}
+
+// formatDuration formats duration in seconds
+func formatDuration(v float64) string {
+	return timeutil.ShortDuration(time.Duration(v * float64(time.Second)))
+}
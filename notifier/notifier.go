@@ -0,0 +1,172 @@
+package notifier
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/log"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	EVENT_START   = "start"
+	EVENT_SUCCESS = "success"
+	EVENT_FAILURE = "failure"
+	EVENT_WARNING = "warning"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Notification contains info about backup event
+type Notification struct {
+	Event     string             `json:"event"`
+	Target    string             `json:"target"`
+	Account   string             `json:"account,omitempty"`
+	Job       string             `json:"job,omitempty"`
+	JobType   string             `json:"job_type,omitempty"`
+	TaskID    string             `json:"task_id,omitempty"`
+	Message   string             `json:"message,omitempty"`
+	Error     string             `json:"error,omitempty"`
+	Duration  float64            `json:"duration"`
+	Durations map[string]float64 `json:"durations,omitempty"`
+	File      string             `json:"file,omitempty"`
+	Size      int64              `json:"size,omitempty"`
+	Checksum  string             `json:"checksum,omitempty"`
+	Location  string             `json:"location,omitempty"`
+	Logs      []string           `json:"logs,omitempty"`
+	Time      time.Time          `json:"time"`
+}
+
+// Notifier is generic notifier interface
+type Notifier interface {
+	// Name returns notifier name
+	Name() string
+
+	// Send sends notification
+	Send(n *Notification) error
+}
+
+// Manager sends notifications to all registered notifiers
+type Manager struct {
+	items []*item
+}
+
+// item contains notifier and list of events it handles
+type item struct {
+	notifier Notifier
+	events   []string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Events is list of all supported events
+var Events = []string{EVENT_START, EVENT_SUCCESS, EVENT_FAILURE, EVENT_WARNING}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewManager creates new notifications manager
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Add adds notifier which handles given events. If events are not set, notifier
+// handles all events.
+func (m *Manager) Add(n Notifier, events ...string) error {
+	if m == nil {
+		return fmt.Errorf("Notifications manager is nil")
+	}
+
+	if n == nil {
+		return fmt.Errorf("Notifier is nil")
+	}
+
+	for _, e := range events {
+		if !slices.Contains(Events, e) {
+			return fmt.Errorf("Unknown event %q for notifier %s", e, n.Name())
+		}
+	}
+
+	if len(events) == 0 {
+		events = Events
+	}
+
+	m.items = append(m.items, &item{n, events})
+
+	return nil
+}
+
+// Notify sends notification to all notifiers which handle notification event
+// and waits until all notifications are sent
+func (m *Manager) Notify(n *Notification) {
+	if m == nil || n == nil {
+		return
+	}
+
+	if n.Time.IsZero() {
+		n.Time = time.Now()
+	}
+
+	var wg sync.WaitGroup
+
+	for _, i := range m.items {
+		if !slices.Contains(i.events, n.Event) {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := i.notifier.Send(n)
+
+			if err != nil {
+				log.Error(
+					"Can't send %s notification: %v", i.notifier.Name(), err,
+					log.F{"event", n.Event}, log.F{"target", n.Target},
+				)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// Size returns number of registered notifiers
+func (m *Manager) Size() int {
+	if m == nil {
+		return 0
+	}
+
+	return len(m.items)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ParseEvents parses list of events
+func ParseEvents(events []string) []string {
+	var result []string
+
+	for _, e := range events {
+		e = strings.ToLower(strings.TrimSpace(e))
+
+		if e != "" {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,501 @@
+package smtp
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	htmlTemplate "html/template"
+	netsmtp "net/smtp"
+	textTemplate "text/template"
+
+	"github.com/essentialkaos/ek/v13/fmtutil"
+	"github.com/essentialkaos/ek/v13/timeutil"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/jobs"
+	"github.com/essentialkaos/atlassian-cloud-backuper/notifier"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	TLS_NONE     = "none"     // Plain connection
+	TLS_STARTTLS = "starttls" // Upgrade plain connection with STARTTLS
+	TLS_IMPLICIT = "tls"      // Implicit TLS (SMTPS)
+)
+
+const (
+	// DEFAULT_PORT is default SMTP port
+	DEFAULT_PORT = 587
+
+	// DEFAULT_TIMEOUT is default connection timeout
+	DEFAULT_TIMEOUT = 30 * time.Second
+)
+
+const (
+	DEFAULT_SUBJECT         = "[{{.Target}}] Backup {{.Event}}"
+	DEFAULT_SUCCESS_SUBJECT = "[{{.Target}}] Backup successfully created"
+	DEFAULT_FAILURE_SUBJECT = "[{{.Target}}] Backup failed"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Config is configuration for SMTP notifier
+type Config struct {
+	Host     string
+	Port     int
+	TLS      string
+	Username string
+	Password string
+	From     string
+
+	To        []string // Default recipients
+	SuccessTo []string // Recipients of success reports (To is used if empty)
+	FailureTo []string // Recipients of failure reports (To is used if empty)
+
+	SuccessSubject string // Subject template for success reports
+	FailureSubject string // Subject template for failure reports
+
+	Timeout time.Duration
+}
+
+// SMTPNotifier sends email reports using SMTP server
+type SMTPNotifier struct {
+	config *Config
+
+	subject        *textTemplate.Template
+	successSubject *textTemplate.Template
+	failureSubject *textTemplate.Template
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Report contains data for message templates
+type Report struct {
+	*notifier.Notification
+
+	Phases   []Phase
+	Errors   []string
+	Subject  string
+	IsFailed bool
+}
+
+// Phase contains info about duration of backup phase
+type Phase struct {
+	Name     string
+	Duration string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// validate default interface implementation
+var _ notifier.Notifier = (*SMTPNotifier)(nil)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// phasesNames contains human-readable names of job phases
+var phasesNames = map[string]string{
+	jobs.PHASE_CREATING:    "Atlassian backup task",
+	jobs.PHASE_DOWNLOADING: "Download",
+	jobs.PHASE_UPLOADING:   "Upload",
+}
+
+// phasesOrder is order of phases in reports
+var phasesOrder = []string{
+	jobs.PHASE_CREATING, jobs.PHASE_DOWNLOADING, jobs.PHASE_UPLOADING,
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewNotifier creates new SMTP notifier instance
+func NewNotifier(config *Config) (*SMTPNotifier, error) {
+	err := config.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if config.TLS == "" {
+		config.TLS = TLS_STARTTLS
+	}
+
+	if config.Port <= 0 {
+		config.Port = DEFAULT_PORT
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = DEFAULT_TIMEOUT
+	}
+
+	n := &SMTPNotifier{config: config}
+
+	n.subject, err = parseSubject(DEFAULT_SUBJECT)
+
+	if err != nil {
+		return nil, err
+	}
+
+	n.successSubject, err = parseSubject(config.SuccessSubject, DEFAULT_SUCCESS_SUBJECT)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't parse success subject template: %w", err)
+	}
+
+	n.failureSubject, err = parseSubject(config.FailureSubject, DEFAULT_FAILURE_SUBJECT)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't parse failure subject template: %w", err)
+	}
+
+	return n, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Name returns notifier name
+func (n *SMTPNotifier) Name() string {
+	return "smtp"
+}
+
+// Send sends email report
+func (n *SMTPNotifier) Send(nt *notifier.Notification) error {
+	if n == nil {
+		return fmt.Errorf("Notifier is nil")
+	}
+
+	report, err := n.getReport(nt)
+
+	if err != nil {
+		return err
+	}
+
+	recipients := n.getRecipients(nt.Event)
+	msg, err := n.render(report, recipients)
+
+	if err != nil {
+		return err
+	}
+
+	return n.send(recipients, msg)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Validate validates configuration
+func (c *Config) Validate() error {
+	switch {
+	case c == nil:
+		return fmt.Errorf("Configuration validation error: config is nil")
+	case c.Host == "":
+		return fmt.Errorf("Configuration validation error: host is empty")
+	case c.From == "":
+		return fmt.Errorf("Configuration validation error: sender address is empty")
+	case len(c.To) == 0 && (len(c.SuccessTo) == 0 || len(c.FailureTo) == 0):
+		return fmt.Errorf("Configuration validation error: recipients are not set")
+	case c.Port < 0 || c.Port > 65535:
+		return fmt.Errorf("Configuration validation error: invalid port %d", c.Port)
+	case !slices.Contains([]string{"", TLS_NONE, TLS_STARTTLS, TLS_IMPLICIT}, c.TLS):
+		return fmt.Errorf("Configuration validation error: unknown TLS mode %q", c.TLS)
+	case c.Password != "" && c.Username == "":
+		return fmt.Errorf("Configuration validation error: username is empty")
+	}
+
+	_, err := mail.ParseAddress(c.From)
+
+	if err != nil {
+		return fmt.Errorf("Configuration validation error: invalid sender address %q", c.From)
+	}
+
+	for _, addr := range slices.Concat(c.To, c.SuccessTo, c.FailureTo) {
+		_, err = mail.ParseAddress(addr)
+
+		if err != nil {
+			return fmt.Errorf("Configuration validation error: invalid recipient address %q", addr)
+		}
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getReport creates report data for given notification
+func (n *SMTPNotifier) getReport(nt *notifier.Notification) (*Report, error) {
+	report := &Report{
+		Notification: nt,
+		IsFailed:     nt.Event == notifier.EVENT_FAILURE,
+	}
+
+	for _, phase := range phasesOrder {
+		dur, ok := nt.Durations[phase]
+
+		if ok {
+			report.Phases = append(report.Phases, Phase{
+				Name:     phasesNames[phase],
+				Duration: formatDuration(dur),
+			})
+		}
+	}
+
+	// Errors are wrapped as "Can't do something: reason", so we can show every
+	// level of error chain on separate line
+	if nt.Error != "" {
+		report.Errors = strings.Split(nt.Error, ": ")
+	}
+
+	subjectTmpl := n.subject
+
+	switch nt.Event {
+	case notifier.EVENT_SUCCESS:
+		subjectTmpl = n.successSubject
+	case notifier.EVENT_FAILURE:
+		subjectTmpl = n.failureSubject
+	}
+
+	var buf bytes.Buffer
+
+	err := subjectTmpl.Execute(&buf, nt)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't render subject: %w", err)
+	}
+
+	report.Subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	return report, nil
+}
+
+// getRecipients returns list of recipients for given event
+func (n *SMTPNotifier) getRecipients(event string) []string {
+	switch {
+	case event == notifier.EVENT_SUCCESS && len(n.config.SuccessTo) != 0:
+		return n.config.SuccessTo
+	case event == notifier.EVENT_FAILURE && len(n.config.FailureTo) != 0:
+		return n.config.FailureTo
+	case len(n.config.To) == 0:
+		return n.config.FailureTo
+	}
+
+	return n.config.To
+}
+
+// render renders message with plain-text and HTML parts
+func (n *SMTPNotifier) render(report *Report, recipients []string) ([]byte, error) {
+	var textBody, htmlBody bytes.Buffer
+
+	err := textMessageTemplate.Execute(&textBody, report)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't render plain-text message: %w", err)
+	}
+
+	err = htmlMessageTemplate.Execute(&htmlBody, report)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't render HTML message: %w", err)
+	}
+
+	var msg, body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+
+	for _, part := range []struct {
+		contentType string
+		data        []byte
+	}{
+		{"text/plain; charset=utf-8", textBody.Bytes()},
+		{"text/html; charset=utf-8", htmlBody.Bytes()},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't create message part: %w", err)
+		}
+
+		qw := quotedprintable.NewWriter(pw)
+		qw.Write(part.data)
+		qw.Close()
+	}
+
+	mw.Close()
+
+	fmt.Fprintf(&msg, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", report.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", report.Time.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: %s\r\n", getMessageID(n.config.From))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mw.Boundary())
+
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+// send sends message to given recipients
+func (n *SMTPNotifier) send(recipients []string, msg []byte) error {
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	dialer := &net.Dialer{Timeout: n.config.Timeout}
+	tlsConfig := &tls.Config{ServerName: n.config.Host}
+
+	var err error
+	var conn net.Conn
+
+	if n.config.TLS == TLS_IMPLICIT {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Can't connect to SMTP server: %w", err)
+	}
+
+	conn.SetDeadline(time.Now().Add(n.config.Timeout))
+
+	client, err := netsmtp.NewClient(conn, n.config.Host)
+
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("Can't create SMTP client: %w", err)
+	}
+
+	defer client.Close()
+
+	if n.config.TLS == TLS_STARTTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP server doesn't support STARTTLS")
+		}
+
+		err = client.StartTLS(tlsConfig)
+
+		if err != nil {
+			return fmt.Errorf("Can't start TLS: %w", err)
+		}
+	}
+
+	if n.config.Username != "" {
+		err = client.Auth(netsmtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host))
+
+		if err != nil {
+			return fmt.Errorf("Can't authenticate on SMTP server: %w", err)
+		}
+	}
+
+	from, _ := mail.ParseAddress(n.config.From)
+	err = client.Mail(from.Address)
+
+	if err != nil {
+		return fmt.Errorf("Can't set sender: %w", err)
+	}
+
+	for _, rcpt := range recipients {
+		to, _ := mail.ParseAddress(rcpt)
+		err = client.Rcpt(to.Address)
+
+		if err != nil {
+			return fmt.Errorf("Can't add recipient %q: %w", rcpt, err)
+		}
+	}
+
+	w, err := client.Data()
+
+	if err != nil {
+		return fmt.Errorf("Can't start sending message: %w", err)
+	}
+
+	_, err = w.Write(msg)
+
+	if err != nil {
+		w.Close()
+		return fmt.Errorf("Can't send message: %w", err)
+	}
+
+	err = w.Close()
+
+	if err != nil {
+		return fmt.Errorf("Can't send message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseSubject parses subject template
+func parseSubject(subject string, defSubject ...string) (*textTemplate.Template, error) {
+	if subject == "" && len(defSubject) != 0 {
+		subject = defSubject[0]
+	}
+
+	return textTemplate.New("subject").Parse(subject)
+}
+
+// getMessageID generates unique message ID
+func getMessageID(from string) string {
+	domain := "localhost"
+	addr, err := mail.ParseAddress(from)
+
+	if err == nil {
+		_, d, ok := strings.Cut(addr.Address, "@")
+
+		if ok {
+			domain = d
+		}
+	}
+
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	return "<" + hex.EncodeToString(id) + "@" + domain + ">"
+}
+
+// formatDuration formats duration in seconds
+func formatDuration(v float64) string {
+	return timeutil.ShortDuration(time.Duration(v * float64(time.Second)))
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// templateFuncs contains helpers available in templates
+var templateFuncs = map[string]any{
+	// size formats size in bytes
+	"size": func(v int64) string {
+		return fmtutil.PrettySize(v)
+	},
+
+	// duration formats duration in seconds
+	"duration": formatDuration,
+}
+
+// textMessageTemplate is template of plain-text message
+var textMessageTemplate = textTemplate.Must(
+	textTemplate.New("text").Funcs(templateFuncs).Parse(textMessage),
+)
+
+// htmlMessageTemplate is template of HTML message
+var htmlMessageTemplate = htmlTemplate.Must(
+	htmlTemplate.New("html").Funcs(templateFuncs).Parse(htmlMessage),
+)
@@ -0,0 +1,137 @@
+package smtp
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// textMessage is plain-text message template
+const textMessage = `{{.Subject}}
+
+Event:    {{.Event}}
+Target:   {{.Target}}
+{{- if .Account}}
+Account:  {{.Account}}
+{{- end}}
+{{- if .Job}}
+Job:      {{.Job}} ({{.JobType}})
+{{- end}}
+Time:     {{.Time.Format "2006-01-02 15:04:05 MST"}}
+{{- if .Message}}
+
+{{.Message}}
+{{- end}}
+{{- if or .Phases .Duration}}
+
+Timings:
+{{- range .Phases}}
+  {{.Name}}: {{.Duration}}
+{{- end}}
+{{- if .Duration}}
+  Total: {{duration .Duration}}
+{{- end}}
+{{- end}}
+{{- if .File}}
+
+Backup:
+  File:     {{.File}}
+{{- if .Size}}
+  Size:     {{size .Size}}
+{{- end}}
+{{- if .Checksum}}
+  Checksum: {{.Checksum}}
+{{- end}}
+{{- if .Location}}
+  Location: {{.Location}}
+{{- end}}
+{{- end}}
+{{- if .Errors}}
+
+Error:
+{{- range .Errors}}
+  {{.}}
+{{- end}}
+{{- end}}
+{{- if .Logs}}
+
+Recent log records:
+{{- range .Logs}}
+  {{.}}
+{{- end}}
+{{- end}}
+
+--
+Atlassian Cloud Backuper
+`
+
+// htmlMessage is HTML message template
+const htmlMessage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Subject}}</title>
+</head>
+<body style="font-family: sans-serif; font-size: 14px; color: #222;">
+<h2 style="color: {{if .IsFailed}}#c62828{{else}}#2e7d32{{end}};">{{.Subject}}</h2>
+<table cellpadding="4" cellspacing="0" style="border-collapse: collapse;">
+<tr><td><b>Event</b></td><td>{{.Event}}</td></tr>
+<tr><td><b>Target</b></td><td>{{.Target}}</td></tr>
+{{- if .Account}}
+<tr><td><b>Account</b></td><td>{{.Account}}</td></tr>
+{{- end}}
+{{- if .Job}}
+<tr><td><b>Job</b></td><td>{{.Job}} ({{.JobType}})</td></tr>
+{{- end}}
+<tr><td><b>Time</b></td><td>{{.Time.Format "2006-01-02 15:04:05 MST"}}</td></tr>
+</table>
+{{- if .Message}}
+<p>{{.Message}}</p>
+{{- end}}
+{{- if or .Phases .Duration}}
+<h3>Timings</h3>
+<table cellpadding="4" cellspacing="0" style="border-collapse: collapse;">
+{{- range .Phases}}
+<tr><td>{{.Name}}</td><td>{{.Duration}}</td></tr>
+{{- end}}
+{{- if .Duration}}
+<tr><td><b>Total</b></td><td><b>{{duration .Duration}}</b></td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .File}}
+<h3>Backup</h3>
+<table cellpadding="4" cellspacing="0" style="border-collapse: collapse;">
+<tr><td><b>File</b></td><td>{{.File}}</td></tr>
+{{- if .Size}}
+<tr><td><b>Size</b></td><td>{{size .Size}}</td></tr>
+{{- end}}
+{{- if .Checksum}}
+<tr><td><b>Checksum</b></td><td><code>{{.Checksum}}</code></td></tr>
+{{- end}}
+{{- if .Location}}
+<tr><td><b>Location</b></td><td>{{.Location}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Errors}}
+<h3>Error</h3>
+<ul style="color: #c62828;">
+{{- range .Errors}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Logs}}
+<h3>Recent log records</h3>
+<pre style="background: #f5f5f5; padding: 8px; font-size: 12px;">
+{{- range .Logs}}
+{{.}}
+{{- end}}
+</pre>
+{{- end}}
+<p style="color: #888; font-size: 12px;">Atlassian Cloud Backuper</p>
+</body>
+</html>
+`
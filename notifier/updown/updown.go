@@ -0,0 +1,95 @@
+package updown
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+
+	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/strutil"
+
+	pulse "github.com/essentialkaos/updown"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/notifier"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Config is configuration for updown.io pulse notifier
+type Config struct {
+	Webhook string
+}
+
+// UpdownNotifier sends pulses to updown.io
+type UpdownNotifier struct {
+	config *Config
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// validate default interface implementation
+var _ notifier.Notifier = (*UpdownNotifier)(nil)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewNotifier creates new updown.io notifier instance
+func NewNotifier(config *Config) (*UpdownNotifier, error) {
+	err := config.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdownNotifier{config}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Name returns notifier name
+func (n *UpdownNotifier) Name() string {
+	return "updown"
+}
+
+// Send sends pulse to updown.io
+func (n *UpdownNotifier) Send(nt *notifier.Notification) error {
+	if n == nil {
+		return fmt.Errorf("Notifier is nil")
+	}
+
+	ok := nt.Event != notifier.EVENT_FAILURE
+	payload := strutil.Q(nt.Error, nt.Message, nt.Event)
+
+	log.Info("Sending pulse request to updown.io…")
+
+	uuid, err := pulse.SendPulse(
+		n.config.Webhook,
+		fmt.Sprintf("%s - %s", strutil.B(ok, "OK", "NOT-OK"), payload),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	log.Info("Pulse successfully sent (%s)", uuid)
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Validate validates configuration
+func (c *Config) Validate() error {
+	switch {
+	case c == nil:
+		return fmt.Errorf("Configuration validation error: config is nil")
+	case c.Webhook == "":
+		return fmt.Errorf("Configuration validation error: webhook URL is empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package webhook
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/fmtutil"
+	"github.com/essentialkaos/ek/v13/req"
+	"github.com/essentialkaos/ek/v13/timeutil"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/notifier"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DEFAULT_TIMEOUT is default request timeout
+const DEFAULT_TIMEOUT = 30 * time.Second
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Config is configuration for webhook notifier
+type Config struct {
+	URL      string
+	Template string // Path to file with body template
+	Timeout  time.Duration
+}
+
+// WebhookNotifier is generic HTTP webhook notifier
+type WebhookNotifier struct {
+	config *Config
+	tmpl   *template.Template
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// validate default interface implementation
+var _ notifier.Notifier = (*WebhookNotifier)(nil)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewNotifier creates new webhook notifier instance
+func NewNotifier(config *Config) (*WebhookNotifier, error) {
+	err := config.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	n := &WebhookNotifier{config: config}
+
+	if config.Timeout <= 0 {
+		config.Timeout = DEFAULT_TIMEOUT
+	}
+
+	if config.Template != "" {
+		data, err := os.ReadFile(config.Template)
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't read webhook template: %w", err)
+		}
+
+		n.tmpl, err = template.New("webhook").Funcs(templateFuncs).Parse(string(data))
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't parse webhook template: %w", err)
+		}
+	}
+
+	return n, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Name returns notifier name
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+// Send sends notification to webhook
+func (n *WebhookNotifier) Send(nt *notifier.Notification) error {
+	if n == nil {
+		return fmt.Errorf("Notifier is nil")
+	}
+
+	body, err := n.render(nt)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := req.Request{
+		URL:         n.config.URL,
+		ContentType: req.CONTENT_TYPE_JSON,
+		Body:        body,
+		Timeout:     n.config.Timeout,
+		AutoDiscard: true,
+	}.Post()
+
+	if err != nil {
+		return fmt.Errorf("Can't send request: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Webhook returned non-ok status code (%d)", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Validate validates configuration
+func (c *Config) Validate() error {
+	switch {
+	case c == nil:
+		return fmt.Errorf("Configuration validation error: config is nil")
+	case c.URL == "":
+		return fmt.Errorf("Configuration validation error: URL is empty")
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// render renders request body for given notification
+func (n *WebhookNotifier) render(nt *notifier.Notification) ([]byte, error) {
+	if n.tmpl == nil {
+		return json.Marshal(nt)
+	}
+
+	var buf bytes.Buffer
+
+	err := n.tmpl.Execute(&buf, nt)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't render webhook template: %w", err)
+	}
+
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("Webhook template rendered invalid JSON")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// templateFuncs contains helpers available in templates
+var templateFuncs = template.FuncMap{
+	// json encodes value as JSON (e.g. {{ json .Error }} gives quoted and
+	// escaped string)
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+
+	// size formats size in bytes
+	"size": func(v int64) string {
+		return fmtutil.PrettySize(v)
+	},
+
+	// duration formats duration in seconds
+	"duration": func(v float64) string {
+		return timeutil.ShortDuration(time.Duration(v * float64(time.Second)))
+	},
+}
@@ -0,0 +1,150 @@
+package runlog
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/strutil"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Log is logger of a single backup run. It writes records to global logger and
+// keeps last records in memory, so they can be added to notifications.
+type Log struct {
+	records []string
+	next    int
+	isFull  bool
+	mu      sync.Mutex
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var _ log.ILogger = (*Log)(nil)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// New creates new run log which keeps given number of last records
+func New(size int) *Log {
+	return &Log{records: make([]string, max(size, 1))}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Aux writes unskippable message
+func (l *Log) Aux(f string, a ...any) error {
+	return l.Print(log.AUX, f, a...)
+}
+
+// Debug writes debug message
+func (l *Log) Debug(f string, a ...any) error {
+	return l.Print(log.DEBUG, f, a...)
+}
+
+// Info writes info message
+func (l *Log) Info(f string, a ...any) error {
+	return l.Print(log.INFO, f, a...)
+}
+
+// Warn writes warning message
+func (l *Log) Warn(f string, a ...any) error {
+	return l.Print(log.WARN, f, a...)
+}
+
+// Error writes error message
+func (l *Log) Error(f string, a ...any) error {
+	return l.Print(log.ERROR, f, a...)
+}
+
+// Crit writes critical message
+func (l *Log) Crit(f string, a ...any) error {
+	return l.Print(log.CRIT, f, a...)
+}
+
+// Print writes message with given level to global logger and saves it to
+// the list of recent records
+func (l *Log) Print(level uint8, f string, a ...any) error {
+	err := log.Global.Print(level, f, a...)
+
+	if l == nil || !log.Global.Is(level) {
+		return err
+	}
+
+	record := formatRecord(time.Now(), level, f, a...)
+
+	l.mu.Lock()
+
+	l.records[l.next] = record
+	l.next = (l.next + 1) % len(l.records)
+	l.isFull = l.isFull || l.next == 0
+
+	l.mu.Unlock()
+
+	return err
+}
+
+// Records returns recent records from the oldest to the newest
+func (l *Log) Records() []string {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.isFull {
+		return append([]string(nil), l.records[:l.next]...)
+	}
+
+	return append(append([]string(nil), l.records[l.next:]...), l.records[:l.next]...)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// formatRecord formats log record in the same way as global logger does it
+// for text logs
+func formatRecord(t time.Time, level uint8, f string, a ...any) string {
+	var args []any
+	var fields []string
+
+	for _, v := range a {
+		switch field := v.(type) {
+		case log.Field:
+			if field.Key != "" {
+				fields = append(fields, fmt.Sprintf("%s: %s", field.Key, strutil.Q(fmt.Sprint(field.Value), "—")))
+			}
+		case log.Fields, *log.Fields:
+			// Fields collections are not supported
+		default:
+			args = append(args, v)
+		}
+	}
+
+	var buf strings.Builder
+
+	buf.WriteString(t.Format(log.DATE_LAYOUT_TEXT))
+	buf.WriteRune(' ')
+
+	if log.PrefixMap[level] != "" {
+		buf.WriteString(log.PrefixMap[level])
+		buf.WriteRune(' ')
+	}
+
+	fmt.Fprintf(&buf, f, args...)
+
+	if len(fields) != 0 {
+		buf.WriteString(" {" + strings.Join(fields, " | ") + "}")
+	}
+
+	return buf.String()
+}
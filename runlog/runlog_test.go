@@ -0,0 +1,101 @@
+package runlog
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/log"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func TestRecords(t *testing.T) {
+	l := New(3)
+
+	if len(l.Records()) != 0 {
+		t.Fatal("New log must be empty")
+	}
+
+	l.Info("record 1")
+	l.Info("record 2")
+
+	checkRecords(t, l.Records(), "record 1", "record 2")
+
+	for i := 3; i <= 7; i++ {
+		l.Info("record %d", i)
+	}
+
+	checkRecords(t, l.Records(), "record 5", "record 6", "record 7")
+}
+
+func TestRecordsIsolation(t *testing.T) {
+	l1, l2 := New(10), New(10)
+
+	l1.Info("first run")
+	l2.Error("second run")
+
+	checkRecords(t, l1.Records(), "first run")
+	checkRecords(t, l2.Records(), "second run")
+}
+
+func TestMinLevel(t *testing.T) {
+	l := New(10)
+
+	l.Debug("debug message")
+	l.Warn("warning message")
+
+	checkRecords(t, l.Records(), "warning message")
+}
+
+func TestNil(t *testing.T) {
+	var l *Log
+
+	l.Info("message")
+
+	if l.Records() != nil {
+		t.Fatal("Nil log must return no records")
+	}
+}
+
+func TestFormatRecord(t *testing.T) {
+	d := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	r := formatRecord(
+		d, log.ERROR, "Can't upload %s", "file.zip",
+		log.F{"job-id", "123"}, log.F{"path", ""},
+	)
+
+	if r != "2025/01/02 03:04:05.000 [ERROR] Can't upload file.zip {job-id: 123 | path: —}" {
+		t.Fatalf("Invalid record %q", r)
+	}
+
+	r = formatRecord(d, log.INFO, "Message", log.NewFields(log.F{"a", 1}))
+
+	if r != "2025/01/02 03:04:05.000 [INFO] Message" {
+		t.Fatalf("Invalid record %q", r)
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func checkRecords(t *testing.T, records []string, messages ...string) {
+	t.Helper()
+
+	if len(records) != len(messages) {
+		t.Fatalf("Invalid number of records %d (%v)", len(records), records)
+	}
+
+	for i, msg := range messages {
+		if !strings.HasSuffix(records[i], fmt.Sprintf(" %s", msg)) {
+			t.Fatalf("Record %d %q doesn't contain message %q", i, records[i], msg)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package scheduler
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/cron"
+	"github.com/essentialkaos/ek/v13/fsutil"
+	"github.com/essentialkaos/ek/v13/jsonutil"
+	"github.com/essentialkaos/ek/v13/log"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Handler is function which executes scheduled task
+type Handler func() error
+
+// Config is scheduler configuration
+type Config struct {
+	// Location is time zone used for schedules
+	Location *time.Location
+
+	// StateFile is path to file with info about previous runs
+	StateFile string
+
+	// CatchUp enables running tasks missed while app was stopped
+	CatchUp bool
+}
+
+// Scheduler is cron-like tasks scheduler
+type Scheduler struct {
+	config *Config
+	tasks  []*task
+	state  map[string]time.Time
+	mu     sync.Mutex
+}
+
+// task contains info about scheduled task
+type task struct {
+	name    string
+	expr    *cron.Expr
+	jitter  time.Duration
+	handler Handler
+	running bool
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	ErrNilConfig     = fmt.Errorf("Configuration validation error: config is nil")
+	ErrEmptyLocation = fmt.Errorf("Configuration validation error: location is empty")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewScheduler creates new scheduler instance
+func NewScheduler(config *Config) (*Scheduler, error) {
+	err := config.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Scheduler{config: config, state: map[string]time.Time{}}
+
+	if config.StateFile != "" && fsutil.IsExist(config.StateFile) {
+		err = jsonutil.Read(config.StateFile, &s.state)
+
+		if err != nil {
+			log.Error("Can't read scheduler state: %v", err)
+		}
+	}
+
+	return s, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Validate validates configuration struct
+func (c *Config) Validate() error {
+	switch {
+	case c == nil:
+		return ErrNilConfig
+	case c.Location == nil:
+		return ErrEmptyLocation
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Add adds new task with given cron expression
+func (s *Scheduler) Add(name, expr string, jitter time.Duration, handler Handler) error {
+	if s == nil {
+		return fmt.Errorf("Scheduler is nil")
+	}
+
+	if handler == nil {
+		return fmt.Errorf("Task handler is nil")
+	}
+
+	cronExpr, err := cron.Parse(expr)
+
+	if err != nil {
+		return fmt.Errorf("Can't parse schedule for %q: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().In(s.config.Location)
+	lastRun, hasState := s.state[name]
+
+	if !hasState || !s.config.CatchUp {
+		s.state[name] = cronExpr.Prev(now)
+	} else if lastRun.Before(cronExpr.Prev(now)) {
+		log.Info("Task %s missed scheduled run and will be started", name)
+	}
+
+	s.tasks = append(s.tasks, &task{
+		name:    name,
+		expr:    cronExpr,
+		jitter:  jitter,
+		handler: handler,
+	})
+
+	log.Info(
+		"Task %s scheduled", name,
+		log.F{"schedule", expr},
+		log.F{"next-run", cronExpr.Next(now).Format(time.RFC3339)},
+	)
+
+	return nil
+}
+
+// Start starts scheduler loop
+func (s *Scheduler) Start() {
+	if s == nil {
+		return
+	}
+
+	s.check()
+
+	go func() {
+		for range time.NewTicker(time.Minute).C {
+			s.check()
+		}
+	}()
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// check checks all tasks and runs tasks which are due
+func (s *Scheduler) check() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().In(s.config.Location)
+
+	for _, t := range s.tasks {
+		due := t.expr.Prev(now)
+
+		if !s.state[t.name].Before(due) {
+			continue
+		}
+
+		s.state[t.name] = due
+		s.saveState()
+
+		if t.running {
+			log.Warn("Task %s skipped: previous run is still in progress", t.name)
+			continue
+		}
+
+		t.running = true
+
+		go s.run(t)
+	}
+}
+
+// run runs task with random delay
+func (s *Scheduler) run(t *task) {
+	if t.jitter > 0 {
+		delay := rand.N(t.jitter)
+		log.Info("Task %s will be started in %s", t.name, delay.Round(time.Second))
+		time.Sleep(delay)
+	}
+
+	log.Info("Starting scheduled task %s", t.name)
+
+	err := t.handler()
+
+	if err != nil {
+		log.Error("Scheduled task %s failed: %v", t.name, err)
+	} else {
+		log.Info("Scheduled task %s successfully finished", t.name)
+	}
+
+	s.mu.Lock()
+	t.running = false
+	s.mu.Unlock()
+}
+
+// saveState saves info about previous runs to state file
+func (s *Scheduler) saveState() {
+	if s.config.StateFile == "" {
+		return
+	}
+
+	err := jsonutil.Write(s.config.StateFile, s.state, 0600)
+
+	if err != nil {
+		log.Error("Can't save scheduler state: %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package uploader
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// CATALOG_FILE is name of backup catalog file in storage
+const CATALOG_FILE = "catalog.json"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Catalog contains info about all backups in storage
+type Catalog struct {
+	Updated time.Time        `json:"updated"`
+	Backups []*CatalogRecord `json:"backups"`
+}
+
+// CatalogRecord contains info about backup in catalog
+type CatalogRecord struct {
+	File       string    `json:"file"`
+	Size       int64     `json:"size"`
+	Checksum   string    `json:"checksum,omitempty"`
+	Encryption string    `json:"encryption,omitempty"`
+	KeyID      string    `json:"key_id,omitempty"`
+	Version    string    `json:"version,omitempty"`
+	Created    time.Time `json:"created"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ReadCatalog reads catalog from storage. Empty catalog is returned if there is
+// no catalog in storage.
+func ReadCatalog(u Uploader) (*Catalog, error) {
+	data, err := u.ReadFile(CATALOG_FILE)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Catalog{}, nil
+		}
+
+		return nil, fmt.Errorf("Can't read catalog: %w", err)
+	}
+
+	catalog := &Catalog{}
+	err = json.Unmarshal(data, catalog)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't decode catalog: %w", err)
+	}
+
+	return catalog, nil
+}
+
+// UpdateCatalog adds info about uploaded backup to catalog in storage
+func UpdateCatalog(u Uploader, meta *Metadata) error {
+	catalog, err := ReadCatalog(u)
+
+	if err != nil {
+		return err
+	}
+
+	catalog.Add(NewCatalogRecord(meta))
+
+	return catalog.Save(u)
+}
+
+// RebuildCatalog creates new catalog using storage listing and backups metadata
+// and saves it to storage
+func RebuildCatalog(u Uploader) (*Catalog, error) {
+	files, err := u.List()
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't list files in storage: %w", err)
+	}
+
+	catalog := &Catalog{}
+	index := map[string]bool{}
+
+	for _, f := range files {
+		index[f.Name] = true
+	}
+
+	for _, f := range files {
+		// Skip service and hidden files (e.g. objects created by storage check)
+		if f.Name == CATALOG_FILE || strings.HasSuffix(f.Name, META_EXT) ||
+			strings.HasPrefix(f.Name, ".") {
+			continue
+		}
+
+		record := &CatalogRecord{File: f.Name, Size: f.Size, Created: f.Modified.UTC()}
+
+		if index[f.Name+META_EXT] {
+			meta, err := readStorageMetadata(u, f.Name+META_EXT)
+
+			if err == nil && meta.File == f.Name {
+				record = NewCatalogRecord(meta)
+			}
+		}
+
+		catalog.Add(record)
+	}
+
+	return catalog, catalog.Save(u)
+}
+
+// NewCatalogRecord creates catalog record from backup metadata
+func NewCatalogRecord(meta *Metadata) *CatalogRecord {
+	record := &CatalogRecord{
+		File:     meta.File,
+		Size:     meta.Size,
+		Checksum: meta.Checksum,
+		Version:  meta.Version,
+		Created:  meta.Created,
+	}
+
+	if meta.IsEncrypted() {
+		record.Encryption = meta.Encryption.Type
+		record.KeyID = meta.Encryption.KeyID
+
+		if record.KeyID == "" {
+			record.KeyID = meta.Encryption.Fingerprint
+		}
+	}
+
+	return record
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Add adds record to catalog or replaces record for the same file
+func (c *Catalog) Add(r *CatalogRecord) {
+	if c == nil || r == nil {
+		return
+	}
+
+	c.Backups = slices.DeleteFunc(c.Backups, func(cr *CatalogRecord) bool {
+		return cr.File == r.File
+	})
+
+	c.Backups = append(c.Backups, r)
+
+	slices.SortStableFunc(c.Backups, func(a, b *CatalogRecord) int {
+		return a.Created.Compare(b.Created)
+	})
+}
+
+// Size returns total size of all backups in catalog
+func (c *Catalog) Size() int64 {
+	var size int64
+
+	if c == nil {
+		return 0
+	}
+
+	for _, r := range c.Backups {
+		size += r.Size
+	}
+
+	return size
+}
+
+// Save saves catalog to storage
+func (c *Catalog) Save(u Uploader) error {
+	c.Updated = time.Now().UTC()
+
+	if c.Backups == nil {
+		c.Backups = []*CatalogRecord{}
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("Can't encode catalog: %w", err)
+	}
+
+	err = u.WriteFile(CATALOG_FILE, data)
+
+	if err != nil {
+		return fmt.Errorf("Can't save catalog: %w", err)
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// readStorageMetadata reads backup metadata from storage
+func readStorageMetadata(u Uploader, fileName string) (*Metadata, error) {
+	data, err := u.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &Metadata{}
+	err = json.Unmarshal(data, meta)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
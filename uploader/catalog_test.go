@@ -0,0 +1,85 @@
+package uploader
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"testing"
+	"time"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func TestCatalogAdd(t *testing.T) {
+	now := time.Now()
+	catalog := &Catalog{}
+
+	catalog.Add(&CatalogRecord{File: "b.zip", Size: 20, Created: now})
+	catalog.Add(&CatalogRecord{File: "a.zip", Size: 10, Created: now.Add(-time.Hour)})
+	catalog.Add(&CatalogRecord{File: "c.zip", Size: 30, Created: now.Add(time.Hour)})
+
+	if len(catalog.Backups) != 3 {
+		t.Fatalf("Invalid number of records %d", len(catalog.Backups))
+	}
+
+	for i, file := range []string{"a.zip", "b.zip", "c.zip"} {
+		if catalog.Backups[i].File != file {
+			t.Fatalf("Records are not sorted (%d: %s)", i, catalog.Backups[i].File)
+		}
+	}
+
+	// Record for the same file must be replaced
+	catalog.Add(&CatalogRecord{File: "a.zip", Size: 15, Created: now.Add(2 * time.Hour)})
+
+	if len(catalog.Backups) != 3 || catalog.Backups[2].File != "a.zip" {
+		t.Fatal("Record is not replaced")
+	}
+
+	if catalog.Size() != 65 {
+		t.Fatalf("Invalid catalog size %d", catalog.Size())
+	}
+
+	catalog.Add(nil)
+
+	if len(catalog.Backups) != 3 {
+		t.Fatal("Nil record must be ignored")
+	}
+}
+
+func TestCatalogNil(t *testing.T) {
+	var catalog *Catalog
+
+	catalog.Add(&CatalogRecord{File: "a.zip"})
+
+	if catalog.Size() != 0 {
+		t.Fatal("Nil catalog must be empty")
+	}
+}
+
+func TestNewCatalogRecord(t *testing.T) {
+	meta := &Metadata{
+		File:     "a.zip",
+		Size:     100,
+		Checksum: "sha256:abcd",
+		Encryption: &encryptor.Info{
+			Type:  "katana",
+			KeyID: "2025-01",
+		},
+	}
+
+	record := NewCatalogRecord(meta)
+
+	if record.File != "a.zip" || record.Size != 100 || record.Checksum != "sha256:abcd" {
+		t.Fatal("Invalid record data")
+	}
+
+	if record.Encryption != "katana" || record.KeyID != "2025-01" {
+		t.Fatalf("Invalid encryption info (%s/%s)", record.Encryption, record.KeyID)
+	}
+}
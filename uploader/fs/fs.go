@@ -8,20 +8,21 @@ package fs
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
-	"bufio"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"time"
 
 	"github.com/essentialkaos/ek/v13/events"
+	"github.com/essentialkaos/ek/v13/fmtutil"
 	"github.com/essentialkaos/ek/v13/fsutil"
 	"github.com/essentialkaos/ek/v13/log"
 	"github.com/essentialkaos/ek/v13/passthru"
 	"github.com/essentialkaos/ek/v13/path"
 
-	"github.com/essentialkaos/katana"
-
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor"
 	"github.com/essentialkaos/atlassian-cloud-backuper/uploader"
 )
 
@@ -29,15 +30,19 @@ import (
 
 // Config is configuration for FS uploader
 type Config struct {
-	Secret *katana.Secret
-	Path   string
-	Mode   os.FileMode
+	Encryptor encryptor.Encryptor
+	Decryptor encryptor.Decryptor
+	Path      string
+	StateDir  string
+	Version   string
+	Mode      os.FileMode
 }
 
 // FSUploader is FS uploader instance
 type FSUploader struct {
 	config     *Config
 	dispatcher *events.Dispatcher
+	logger     log.ILogger
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -55,7 +60,7 @@ func NewUploader(config *Config) (*FSUploader, error) {
 		return nil, err
 	}
 
-	return &FSUploader{config, nil}, nil
+	return &FSUploader{config: config, logger: log.Global}, nil
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -67,6 +72,19 @@ func (u *FSUploader) SetDispatcher(d *events.Dispatcher) {
 	}
 }
 
+// SetLogger sets logger for process messages
+func (u *FSUploader) SetLogger(l log.ILogger) {
+	if u == nil {
+		return
+	}
+
+	if l == nil {
+		l = log.Global
+	}
+
+	u.logger = l
+}
+
 // Upload uploads given file to storage
 func (u *FSUploader) Upload(file, fileName string) error {
 	err := fsutil.ValidatePerms("FRS", file)
@@ -83,7 +101,7 @@ func (u *FSUploader) Upload(file, fileName string) error {
 		}
 	}
 
-	fd, err := os.Open(file)
+	fd, err := uploader.OpenSource(file, u.config.Decryptor)
 
 	if err != nil {
 		return fmt.Errorf("Can't open backup file: %w", err)
@@ -91,17 +109,107 @@ func (u *FSUploader) Upload(file, fileName string) error {
 
 	defer fd.Close()
 
-	err = u.Write(fd, fileName, fsutil.GetSize(file))
+	state, err := u.getState(file, fileName)
 
 	if err != nil {
+		u.logger.Warn("Upload resuming is disabled: %v", err)
+	}
+
+	// Size of encrypted source file differs from size of its data, so exact
+	// size from state is used if possible
+	size := fsutil.GetSize(file)
+
+	if state != nil {
+		size = state.Size
+	}
+
+	err = u.write(fd, fileName, size, state)
+
+	if err != nil {
+		state.Save()
 		return fmt.Errorf("Can't save backup file: %w", err)
 	}
 
-	return err
+	state.Delete()
+
+	return nil
 }
 
 // Write writes data from given reader to given file
 func (u *FSUploader) Write(r io.ReadCloser, fileName string, fileSize int64) error {
+	return u.write(r, fileName, fileSize, nil)
+}
+
+// Check checks storage availability
+func (u *FSUploader) Check() error {
+	dir := u.config.Path
+
+	for !fsutil.IsExist(dir) && dir != "/" && dir != "." {
+		dir = path.Dir(dir)
+	}
+
+	return fsutil.ValidatePerms("DWX", dir)
+}
+
+// List returns info about all files in storage
+func (u *FSUploader) List() ([]*uploader.FileInfo, error) {
+	entries, err := os.ReadDir(u.config.Path)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var result []*uploader.FileInfo
+
+	for _, e := range entries {
+		info, err := e.Info()
+
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+
+		result = append(result, &uploader.FileInfo{
+			Name:     info.Name(),
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+		})
+	}
+
+	return result, nil
+}
+
+// ReadFile reads small file (metadata or catalog) from storage
+func (u *FSUploader) ReadFile(fileName string) ([]byte, error) {
+	return os.ReadFile(path.Join(u.config.Path, fileName))
+}
+
+// WriteFile writes small file (metadata or catalog) to storage
+func (u *FSUploader) WriteFile(fileName string, data []byte) error {
+	if !fsutil.IsExist(u.config.Path) {
+		err := os.MkdirAll(u.config.Path, 0750)
+
+		if err != nil {
+			return fmt.Errorf("Can't create directory for backup: %w", err)
+		}
+	}
+
+	return os.WriteFile(path.Join(u.config.Path, fileName), data, u.config.Mode)
+}
+
+// DeleteFile removes file from storage
+func (u *FSUploader) DeleteFile(fileName string) error {
+	return os.Remove(path.Join(u.config.Path, fileName))
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// write writes data from given reader to given file and resumes previously
+// interrupted writing if state is provided
+func (u *FSUploader) write(r io.Reader, fileName string, fileSize int64, state *uploader.State) error {
 	u.dispatcher.DispatchAndWait(uploader.EVENT_UPLOAD_STARTED, "FS")
 
 	var w io.Writer
@@ -109,30 +217,50 @@ func (u *FSUploader) Write(r io.ReadCloser, fileName string, fileSize int64) err
 	lastUpdate := time.Now()
 	outputFile := path.Join(u.config.Path, fileName)
 
-	log.Info("Copying backup file to %s…", u.config.Path)
+	u.logger.Info("Copying backup file to %s…", u.config.Path)
 
-	fd, err := os.OpenFile(outputFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, u.config.Mode)
+	fd, offset, err := u.openOutputFile(outputFile, state)
 
 	if err != nil {
 		return err
 	}
 
+	defer fd.Close()
+
+	hasher := sha256.New()
+
+	if offset > 0 {
+		// Already written data is read through hasher instead of seeking, so
+		// checksum covers the whole file
+		_, err = io.CopyN(hasher, r, offset)
+
+		if err != nil {
+			return fmt.Errorf("Can't read backup file: %w", err)
+		}
+
+		u.logger.Info("Resuming interrupted copying from %s", fmtutil.PrettySize(offset))
+	}
+
 	w = fd
 
-	if u.config.Secret != nil {
-		sw, err := u.config.Secret.NewWriter(fd)
+	if state != nil {
+		w = uploader.NewStateWriter(fd, state)
+	}
+
+	var ew io.WriteCloser
+
+	if u.config.Encryptor != nil {
+		ew, err = u.config.Encryptor.NewWriter(w)
 
 		if err != nil {
 			return fmt.Errorf("Can't create encrypted writer: %w", err)
 		}
 
-		defer sw.Close()
-
-		w = sw
+		w = ew
 	}
 
 	if fileSize > 0 {
-		pw := passthru.NewWriter(w, fileSize)
+		pw := passthru.NewWriter(w, fileSize-offset)
 
 		pw.Update = func(n int) {
 			if time.Since(lastUpdate) < 3*time.Second {
@@ -142,9 +270,9 @@ func (u *FSUploader) Write(r io.ReadCloser, fileName string, fileSize int64) err
 			u.dispatcher.Dispatch(
 				uploader.EVENT_UPLOAD_PROGRESS,
 				&uploader.ProgressInfo{
-					Progress: pw.Progress(),
-					Current:  pw.Current(),
-					Total:    pw.Total(),
+					Progress: float64(offset+pw.Current()) / float64(fileSize) * 100,
+					Current:  offset + pw.Current(),
+					Total:    fileSize,
 				},
 			)
 
@@ -154,18 +282,127 @@ func (u *FSUploader) Write(r io.ReadCloser, fileName string, fileSize int64) err
 		w = pw
 	}
 
-	_, err = io.Copy(bufio.NewWriter(w), r)
+	n, err := io.Copy(w, io.TeeReader(r, hasher))
 
 	if err != nil {
+		if state == nil {
+			// Partial file can't be resumed without state, so we remove it
+			os.Remove(outputFile)
+		}
+
 		return fmt.Errorf("File writing error: %w", err)
 	}
 
+	// Encrypting writer writes the final chunk and authentication tag on close,
+	// so file is complete only after successful closing of both writers
+	if ew != nil {
+		err = ew.Close()
+	}
+
+	if err == nil {
+		err = fd.Close()
+
+		// Some encrypting writers close underlying writer by themselves
+		if errors.Is(err, os.ErrClosed) {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		if state == nil {
+			os.Remove(outputFile)
+		}
+
+		return fmt.Errorf("Can't finalize backup file: %w", err)
+	}
+
+	meta := uploader.NewMetadata(fileName, offset+n, u.config.Encryptor)
+	meta.Checksum = uploader.FormatChecksum(hasher)
+	meta.Version = u.config.Version
+
+	err = u.writeMetadata(meta)
+
+	if err != nil {
+		if u.config.Encryptor != nil {
+			// Metadata contains info required for decrypting backup, so encrypted
+			// backup without metadata is removed and uploading is considered failed
+			os.Remove(outputFile)
+			return fmt.Errorf("Can't save backup metadata: %w", err)
+		}
+
+		u.logger.Error("Can't save backup metadata: %v", err)
+	}
+
+	err = uploader.UpdateCatalog(u, meta)
+
+	if err != nil {
+		u.logger.Error("Can't update backup catalog: %v", err)
+	}
+
 	u.dispatcher.DispatchAndWait(uploader.EVENT_UPLOAD_DONE, "FS")
-	log.Info("Backup successfully copied to %s", u.config.Path)
+	u.logger.Info("Backup successfully copied to %s", u.config.Path)
 
 	return nil
 }
 
+// openOutputFile opens output file for writing and returns offset for resuming
+// interrupted writing
+func (u *FSUploader) openOutputFile(outputFile string, state *uploader.State) (*os.File, int64, error) {
+	if !state.IsResumed() || fsutil.GetSize(outputFile) < state.Offset {
+		state.Reset()
+
+		fd, err := os.OpenFile(outputFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, u.config.Mode)
+
+		return fd, 0, err
+	}
+
+	fd, err := os.OpenFile(outputFile, os.O_WRONLY, u.config.Mode)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = fd.Truncate(state.Offset)
+
+	if err == nil {
+		_, err = fd.Seek(state.Offset, io.SeekStart)
+	}
+
+	if err != nil {
+		fd.Close()
+		return nil, 0, fmt.Errorf("Can't prepare file for resuming: %w", err)
+	}
+
+	return fd, state.Offset, nil
+}
+
+// writeMetadata writes backup metadata file
+func (u *FSUploader) writeMetadata(meta *uploader.Metadata) error {
+	data, err := meta.Encode()
+
+	if err != nil {
+		return err
+	}
+
+	return u.WriteFile(meta.File+uploader.META_EXT, data)
+}
+
+// getState returns upload state if resuming is supported for current configuration
+func (u *FSUploader) getState(file, fileName string) (*uploader.State, error) {
+	switch {
+	case u.config.StateDir == "":
+		return nil, nil
+	case u.config.Encryptor != nil:
+		return nil, uploader.ErrResumeEncrypted
+	}
+
+	return uploader.NewState(
+		u.config.StateDir, file,
+		"fs:"+path.Join(u.config.Path, fileName),
+		u.config.Decryptor,
+	)
+}
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 // Validate validates configuration
@@ -0,0 +1,236 @@
+package fs
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/essentialkaos/ek/v13/log"
+	"github.com/essentialkaos/ek/v13/path"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor"
+	"github.com/essentialkaos/atlassian-cloud-backuper/uploader"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func TestUploadResume(t *testing.T) {
+	dir := t.TempDir()
+	source := path.Join(dir, "source.zip")
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+
+	os.WriteFile(source, data, 0600)
+
+	u, err := NewUploader(&Config{
+		Path:     path.Join(dir, "backups"),
+		StateDir: dir,
+		Mode:     0600,
+	})
+
+	if err != nil {
+		t.Fatalf("Can't create uploader: %v", err)
+	}
+
+	// Simulate interrupted upload with some garbage after saved offset
+	os.MkdirAll(u.config.Path, 0750)
+	os.WriteFile(path.Join(u.config.Path, "backup.zip"), append(data[:4000:4000], "garbage"...), 0600)
+
+	state, _ := u.getState(source, "backup.zip")
+	state.Offset = 4000
+	state.Save()
+
+	err = u.Upload(source, "backup.zip")
+
+	if err != nil {
+		t.Fatalf("Can't upload file: %v", err)
+	}
+
+	result, _ := os.ReadFile(path.Join(u.config.Path, "backup.zip"))
+
+	if !bytes.Equal(result, data) {
+		t.Fatalf("Resumed file is corrupted (size: %d)", len(result))
+	}
+
+	catalog, err := uploader.ReadCatalog(u)
+
+	if err != nil || len(catalog.Backups) != 1 || catalog.Backups[0].Size != int64(len(data)) {
+		t.Fatal("Catalog is not updated")
+	}
+}
+
+func TestUploadEncryptedSourceResume(t *testing.T) {
+	dir := t.TempDir()
+	source := path.Join(dir, "source.enc")
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+
+	os.WriteFile(source, append([]byte("header-1"), data...), 0600)
+
+	u, _ := NewUploader(&Config{
+		Decryptor: testDecryptor{},
+		Path:      path.Join(dir, "backups"),
+		StateDir:  dir,
+		Mode:      0600,
+	})
+
+	os.MkdirAll(u.config.Path, 0750)
+	os.WriteFile(path.Join(u.config.Path, "backup.zip"), data[:4000], 0600)
+
+	state, err := u.getState(source, "backup.zip")
+
+	if err != nil {
+		t.Fatalf("Can't create state: %v", err)
+	}
+
+	state.Offset = 4000
+	state.Save()
+
+	// Temporary file is encrypted again with another key after restart
+	os.WriteFile(source, append([]byte("header-2"), data...), 0600)
+
+	err = u.Upload(source, "backup.zip")
+
+	if err != nil {
+		t.Fatalf("Can't upload file: %v", err)
+	}
+
+	result, _ := os.ReadFile(path.Join(u.config.Path, "backup.zip"))
+
+	if !bytes.Equal(result, data) {
+		t.Fatalf("Resumed file is corrupted (size: %d)", len(result))
+	}
+}
+
+func TestOpenOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	file := path.Join(dir, "backup.zip")
+	u := &FSUploader{config: &Config{Path: dir, Mode: 0600}, logger: log.Global}
+
+	os.WriteFile(file, []byte("0123456789"), 0600)
+
+	// Output file is shorter than saved offset, so writing must be started
+	// from scratch
+	state := &uploader.State{Offset: 100}
+	fd, offset, err := u.openOutputFile(file, state)
+
+	if err != nil {
+		t.Fatalf("Can't open output file: %v", err)
+	}
+
+	fd.Close()
+
+	if offset != 0 || state.IsResumed() {
+		t.Fatalf("Invalid offset %d", offset)
+	}
+
+	os.WriteFile(file, []byte("0123456789"), 0600)
+
+	state = &uploader.State{Offset: 4}
+	fd, offset, err = u.openOutputFile(file, state)
+
+	if err != nil {
+		t.Fatalf("Can't open output file: %v", err)
+	}
+
+	fd.Write([]byte("AB"))
+	fd.Close()
+
+	result, _ := os.ReadFile(file)
+
+	if offset != 4 || string(result) != "0123AB" {
+		t.Fatalf("Invalid resumed data %q (offset: %d)", result, offset)
+	}
+}
+
+func TestWriteEncryptedFinalizeError(t *testing.T) {
+	dir := t.TempDir()
+
+	u, _ := NewUploader(&Config{
+		Encryptor: &testEncryptor{closeErr: errors.New("can't write authentication tag")},
+		Path:      dir,
+		Mode:      0600,
+	})
+
+	err := u.Write(io.NopCloser(strings.NewReader("data")), "backup.zip", 4)
+
+	if err == nil {
+		t.Fatal("Finalization error must be returned")
+	}
+
+	if _, err = os.Stat(path.Join(dir, "backup.zip")); err == nil {
+		t.Fatal("Unfinished file must be removed")
+	}
+
+	if _, err = os.Stat(path.Join(dir, uploader.CATALOG_FILE)); err == nil {
+		t.Fatal("Unfinished file must not be added to catalog")
+	}
+}
+
+func TestWriteEncryptedMetadataError(t *testing.T) {
+	dir := t.TempDir()
+
+	u, _ := NewUploader(&Config{
+		Encryptor: &testEncryptor{},
+		Path:      dir,
+		Mode:      0600,
+	})
+
+	// Directory with the name of metadata file makes metadata writing fail
+	os.Mkdir(path.Join(dir, "backup.zip"+uploader.META_EXT), 0700)
+
+	err := u.Write(io.NopCloser(strings.NewReader("data")), "backup.zip", 4)
+
+	if err == nil {
+		t.Fatal("Metadata error must be returned for encrypted backup")
+	}
+
+	if _, err = os.Stat(path.Join(dir, "backup.zip")); err == nil {
+		t.Fatal("Encrypted backup without metadata must be removed")
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// testEncryptor is encryptor which doesn't change data
+type testEncryptor struct {
+	closeErr error
+}
+
+// testWriter is writer which returns given error on closing
+type testWriter struct {
+	io.Writer
+	closeErr error
+}
+
+func (e *testEncryptor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return &testWriter{w, e.closeErr}, nil
+}
+
+func (e *testEncryptor) NewReader(r io.Reader) (io.Reader, error) {
+	return r, nil
+}
+
+func (e *testEncryptor) Info() *encryptor.Info {
+	return &encryptor.Info{Type: "test"}
+}
+
+func (w *testWriter) Close() error {
+	return w.closeErr
+}
+
+// testDecryptor is decryptor which skips fixed size header
+type testDecryptor struct{}
+
+func (d testDecryptor) NewDecryptReader(r io.Reader) (io.Reader, error) {
+	_, err := io.CopyN(io.Discard, r, 8)
+	return r, err
+}
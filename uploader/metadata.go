@@ -0,0 +1,82 @@
+package uploader
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/jsonutil"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// META_EXT is extension of backup metadata (sidecar) files
+const META_EXT = ".meta.json"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Metadata contains backup metadata stored alongside backup file
+type Metadata struct {
+	File       string          `json:"file"`
+	Size       int64           `json:"size"`
+	Checksum   string          `json:"checksum,omitempty"`
+	Version    string          `json:"version,omitempty"`
+	Created    time.Time       `json:"created"`
+	Encryption *encryptor.Info `json:"encryption,omitempty"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewMetadata creates metadata for given backup file
+func NewMetadata(fileName string, size int64, enc encryptor.Encryptor) *Metadata {
+	meta := &Metadata{
+		File:    fileName,
+		Size:    size,
+		Created: time.Now().UTC(),
+	}
+
+	if enc != nil {
+		meta.Encryption = enc.Info()
+	}
+
+	return meta
+}
+
+// ReadMetadata reads metadata from given file
+func ReadMetadata(file string) (*Metadata, error) {
+	meta := &Metadata{}
+	err := jsonutil.Read(file, meta)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// FormatChecksum returns SHA-256 checksum from given hash in metadata format
+func FormatChecksum(h hash.Hash) string {
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// IsEncrypted returns true if backup is encrypted
+func (m *Metadata) IsEncrypted() bool {
+	return m != nil && m.Encryption != nil
+}
+
+// Encode encodes metadata to JSON
+func (m *Metadata) Encode() ([]byte, error) {
+	return json.MarshalIndent(m, "", "  ")
+}
@@ -8,7 +8,10 @@ package s3
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -16,6 +19,7 @@ import (
 	"time"
 
 	"github.com/essentialkaos/ek/v13/events"
+	"github.com/essentialkaos/ek/v13/fmtutil"
 	"github.com/essentialkaos/ek/v13/fsutil"
 	"github.com/essentialkaos/ek/v13/log"
 	"github.com/essentialkaos/ek/v13/passthru"
@@ -25,9 +29,10 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 
-	"github.com/essentialkaos/katana"
-
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor"
 	"github.com/essentialkaos/atlassian-cloud-backuper/uploader"
 )
 
@@ -35,7 +40,8 @@ import (
 
 // Config is configuration for S3 uploader
 type Config struct {
-	Secret *katana.Secret
+	Encryptor encryptor.Encryptor
+	Decryptor encryptor.Decryptor
 
 	Host        string
 	Region      string
@@ -44,12 +50,17 @@ type Config struct {
 	Bucket      string
 	Path        string
 	PartSize    uint64
+	Version     string
+
+	StateDir     string
+	StaleTimeout time.Duration
 }
 
 // S3Uploader is S3 uploader instance
 type S3Uploader struct {
 	config     *Config
 	dispatcher *events.Dispatcher
+	logger     log.ILogger
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -67,7 +78,7 @@ func NewUploader(config *Config) (*S3Uploader, error) {
 		return nil, err
 	}
 
-	return &S3Uploader{config, nil}, nil
+	return &S3Uploader{config: config, logger: log.Global}, nil
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -79,9 +90,22 @@ func (u *S3Uploader) SetDispatcher(d *events.Dispatcher) {
 	}
 }
 
+// SetLogger sets logger for process messages
+func (u *S3Uploader) SetLogger(l log.ILogger) {
+	if u == nil {
+		return
+	}
+
+	if l == nil {
+		l = log.Global
+	}
+
+	u.logger = l
+}
+
 // Upload uploads given file to S3 storage
 func (u *S3Uploader) Upload(file, fileName string) error {
-	fd, err := os.Open(file)
+	fd, err := uploader.OpenSource(file, u.config.Decryptor)
 
 	if err != nil {
 		return fmt.Errorf("Can't open backup file for reading: %v", err)
@@ -89,12 +113,33 @@ func (u *S3Uploader) Upload(file, fileName string) error {
 
 	defer fd.Close()
 
-	err = u.Write(fd, fileName, fsutil.GetSize(file))
+	state, err := u.getState(file, fileName)
+
+	if err != nil {
+		u.logger.Warn("Upload resuming is disabled: %v", err)
+	}
+
+	// Size of encrypted source file differs from size of its data, so exact
+	// size from state is used if possible
+	size := fsutil.GetSize(file)
+
+	if state != nil {
+		size = state.Size
+	}
+
+	if state == nil {
+		err = u.Write(fd, fileName, size)
+	} else {
+		err = u.uploadMultipart(fd, fileName, size, state)
+	}
 
 	if err != nil {
+		state.Save()
 		return fmt.Errorf("Can't save backup: %w", err)
 	}
 
+	state.Delete()
+
 	return nil
 }
 
@@ -106,32 +151,16 @@ func (u *S3Uploader) Write(r io.ReadCloser, fileName string, fileSize int64) err
 	var err error
 
 	lastUpdate := time.Now()
-	outputFile := fileName
-
-	if u.config.Path != "" {
-		outputFile = path.Join(u.config.Path, fileName)
-	}
+	outputFile := u.getOutputFile(fileName)
 
-	log.Info(
+	u.logger.Info(
 		"Uploading backup file to %s:%s (%s/%s)",
 		u.config.Bucket, u.config.Path, u.config.Host, u.config.Region,
 	)
 
-	rr = r
-
-	if u.config.Secret != nil {
-		sr, err := u.config.Secret.NewReader(r, katana.MODE_ENCRYPT)
-
-		if err != nil {
-			return fmt.Errorf("Can't create encrypted reader: %w", err)
-		}
-
-		rr = sr
-	}
+	pr := passthru.NewReader(r, fileSize)
 
 	if fileSize > 0 {
-		pr := passthru.NewReader(rr, fileSize)
-
 		pr.Update = func(n int) {
 			if time.Since(lastUpdate) < 3*time.Second {
 				return
@@ -145,19 +174,25 @@ func (u *S3Uploader) Write(r io.ReadCloser, fileName string, fileSize int64) err
 					Total:    pr.Total(),
 				},
 			)
-		}
 
-		rr = pr
+			lastUpdate = time.Now()
+		}
 	}
 
-	client := s3.New(s3.Options{
-		Region:       u.config.Region,
-		BaseEndpoint: aws.String("https://" + u.config.Host),
-		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(
-			u.config.AccessKeyID, u.config.SecretKey, "",
-		)),
-	})
+	hasher := sha256.New()
+	rr = io.TeeReader(pr, hasher)
 
+	if u.config.Encryptor != nil {
+		sr, err := u.config.Encryptor.NewReader(rr)
+
+		if err != nil {
+			return fmt.Errorf("Can't create encrypted reader: %w", err)
+		}
+
+		rr = sr
+	}
+
+	client := u.getClient()
 	manager := manager.NewUploader(client, func(c *manager.Uploader) {
 		c.PartSize = int64(u.config.PartSize)
 	})
@@ -172,12 +207,424 @@ func (u *S3Uploader) Write(r io.ReadCloser, fileName string, fileSize int64) err
 		return fmt.Errorf("Can't upload file to S3: %v", err)
 	}
 
-	log.Info("File successfully uploaded to S3!")
+	meta := uploader.NewMetadata(fileName, pr.Current(), u.config.Encryptor)
+	meta.Checksum = uploader.FormatChecksum(hasher)
+	meta.Version = u.config.Version
+
+	err = u.writeMetadata(client, meta)
+
+	if err != nil {
+		if u.config.Encryptor != nil {
+			// Metadata contains info required for decrypting backup, so encrypted
+			// backup without metadata is removed and uploading is considered failed
+			u.DeleteFile(fileName)
+			return fmt.Errorf("Can't save backup metadata: %w", err)
+		}
+
+		u.logger.Error("Can't save backup metadata: %v", err)
+	}
+
+	err = uploader.UpdateCatalog(u, meta)
+
+	if err != nil {
+		u.logger.Error("Can't update backup catalog: %v", err)
+	}
+
+	u.logger.Info("File successfully uploaded to S3!")
+	u.dispatcher.DispatchAndWait(uploader.EVENT_UPLOAD_DONE, "S3")
+
+	return nil
+}
+
+// Check checks storage availability
+func (u *S3Uploader) Check() error {
+	_, err := u.getClient().HeadBucket(context.TODO(), &s3.HeadBucketInput{
+		Bucket: aws.String(u.config.Bucket),
+	})
+
+	if err != nil {
+		return fmt.Errorf("Can't access bucket %q: %w", u.config.Bucket, err)
+	}
+
+	return nil
+}
+
+// List returns info about all files in storage
+func (u *S3Uploader) List() ([]*uploader.FileInfo, error) {
+	var result []*uploader.FileInfo
+
+	prefix := path.Clean(u.config.Path) + "/"
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(u.config.Bucket),
+		Prefix: aws.String(prefix),
+	}
+
+	paginator := s3.NewListObjectsV2Paginator(u.getClient(), input)
+
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(context.TODO())
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't list objects in bucket %q: %w", u.config.Bucket, err)
+		}
+
+		for _, obj := range resp.Contents {
+			name := strings.TrimPrefix(aws.ToString(obj.Key), prefix)
+
+			// Skip objects from nested directories
+			if name == "" || strings.Contains(name, "/") {
+				continue
+			}
+
+			result = append(result, &uploader.FileInfo{
+				Name:     name,
+				Size:     aws.ToInt64(obj.Size),
+				Modified: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// ReadFile reads small file (metadata or catalog) from storage
+func (u *S3Uploader) ReadFile(fileName string) ([]byte, error) {
+	var errNoKey *types.NoSuchKey
+
+	resp, err := u.getClient().GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(u.config.Bucket),
+		Key:    aws.String(u.getOutputFile(fileName)),
+	})
+
+	if err != nil {
+		if errors.As(err, &errNoKey) {
+			return nil, fmt.Errorf("Object %q not found: %w", fileName, os.ErrNotExist)
+		}
+
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+// WriteFile writes small file (metadata or catalog) to storage
+func (u *S3Uploader) WriteFile(fileName string, data []byte) error {
+	return u.putObject(u.getClient(), fileName, data)
+}
+
+// DeleteFile removes file from storage
+func (u *S3Uploader) DeleteFile(fileName string) error {
+	_, err := u.getClient().DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+		Bucket: aws.String(u.config.Bucket),
+		Key:    aws.String(u.getOutputFile(fileName)),
+	})
+
+	return err
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// uploadMultipart uploads file using multipart upload and resumes previously
+// interrupted upload if state contains info about it
+func (u *S3Uploader) uploadMultipart(fd io.ReadCloser, fileName string, fileSize int64, state *uploader.State) error {
+	if fileSize == 0 {
+		return u.Write(fd, fileName, fileSize)
+	}
+
+	u.dispatcher.DispatchAndWait(uploader.EVENT_UPLOAD_STARTED, "S3")
+
+	lastUpdate := time.Now()
+	client := u.getClient()
+	outputFile := u.getOutputFile(fileName)
+
+	u.logger.Info(
+		"Uploading backup file to %s:%s (%s/%s)",
+		u.config.Bucket, u.config.Path, u.config.Host, u.config.Region,
+	)
+
+	u.abortStaleUploads(client, state)
+
+	if state.IsResumed() {
+		isExist, err := u.isUploadExist(client, outputFile, state.UploadID)
+
+		if err != nil {
+			return fmt.Errorf("Can't check multipart upload %s: %v", state.UploadID, err)
+		}
+
+		if !isExist {
+			u.logger.Warn("Multipart upload %s not found, starting new upload", state.UploadID)
+			state.Reset()
+		}
+	}
+
+	if state.UploadID == "" {
+		resp, err := client.CreateMultipartUpload(context.TODO(), &s3.CreateMultipartUploadInput{
+			Bucket: aws.String(u.config.Bucket),
+			Key:    aws.String(outputFile),
+		})
+
+		if err != nil {
+			return fmt.Errorf("Can't create multipart upload: %v", err)
+		}
+
+		state.UploadID = aws.ToString(resp.UploadId)
+		state.Save()
+	}
+
+	hasher := sha256.New()
+	offset := state.Uploaded()
+
+	if offset > 0 {
+		// Already uploaded parts are read through hasher instead of seeking, so
+		// checksum covers the whole file
+		_, err := io.CopyN(hasher, fd, offset)
+
+		if err != nil {
+			return fmt.Errorf("Can't read backup file: %w", err)
+		}
+
+		u.logger.Info("Resuming interrupted uploading from %s", fmtutil.PrettySize(offset))
+	}
+
+	buf := make([]byte, u.config.PartSize)
+	partNum := int32(len(state.Parts) + 1)
+
+	for {
+		n, err := io.ReadFull(fd, buf)
+
+		if n == 0 {
+			break
+		}
+
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return fmt.Errorf("Can't read backup file: %w", err)
+		}
+
+		hasher.Write(buf[:n])
+
+		resp, err := client.UploadPart(context.TODO(), &s3.UploadPartInput{
+			Bucket:        aws.String(u.config.Bucket),
+			Key:           aws.String(outputFile),
+			UploadId:      aws.String(state.UploadID),
+			PartNumber:    aws.Int32(partNum),
+			Body:          bytes.NewReader(buf[:n]),
+			ContentLength: aws.Int64(int64(n)),
+		})
+
+		if err != nil {
+			return fmt.Errorf("Can't upload part %d to S3: %v", partNum, err)
+		}
+
+		state.AddPart(partNum, aws.ToString(resp.ETag), int64(n))
+		state.Save()
+
+		partNum++
+
+		if time.Since(lastUpdate) >= 3*time.Second {
+			u.dispatcher.Dispatch(
+				uploader.EVENT_UPLOAD_PROGRESS,
+				&uploader.ProgressInfo{
+					Progress: float64(state.Uploaded()) / float64(fileSize) * 100,
+					Current:  state.Uploaded(),
+					Total:    fileSize,
+				},
+			)
+
+			lastUpdate = time.Now()
+		}
+	}
+
+	var parts []types.CompletedPart
+
+	for _, p := range state.Parts {
+		parts = append(parts, types.CompletedPart{
+			ETag:       aws.String(p.ETag),
+			PartNumber: aws.Int32(p.Num),
+		})
+	}
+
+	_, err := client.CompleteMultipartUpload(context.TODO(), &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(u.config.Bucket),
+		Key:             aws.String(outputFile),
+		UploadId:        aws.String(state.UploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+
+	if err != nil {
+		return fmt.Errorf("Can't complete multipart upload: %v", err)
+	}
+
+	meta := uploader.NewMetadata(fileName, state.Uploaded(), u.config.Encryptor)
+	meta.Checksum = uploader.FormatChecksum(hasher)
+	meta.Version = u.config.Version
+
+	err = u.writeMetadata(client, meta)
+
+	if err != nil {
+		if u.config.Encryptor != nil {
+			// Metadata contains info required for decrypting backup, so encrypted
+			// backup without metadata is removed and uploading is considered failed
+			u.DeleteFile(fileName)
+			return fmt.Errorf("Can't save backup metadata: %w", err)
+		}
+
+		u.logger.Error("Can't save backup metadata: %v", err)
+	}
+
+	err = uploader.UpdateCatalog(u, meta)
+
+	if err != nil {
+		u.logger.Error("Can't update backup catalog: %v", err)
+	}
+
+	u.logger.Info("File successfully uploaded to S3!")
 	u.dispatcher.DispatchAndWait(uploader.EVENT_UPLOAD_DONE, "S3")
 
 	return nil
 }
 
+// isUploadExist returns true if multipart upload with given ID exists. Only
+// NoSuchUpload error means that upload doesn't exist, any other error is
+// returned as is, so state of upload is kept.
+func (u *S3Uploader) isUploadExist(client *s3.Client, outputFile, uploadID string) (bool, error) {
+	var apiErr smithy.APIError
+
+	_, err := client.ListParts(context.TODO(), &s3.ListPartsInput{
+		Bucket:   aws.String(u.config.Bucket),
+		Key:      aws.String(outputFile),
+		UploadId: aws.String(uploadID),
+		MaxParts: aws.Int32(1),
+	})
+
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchUpload":
+		return false, nil
+	}
+
+	return false, err
+}
+
+// abortStaleUploads aborts multipart uploads which are older than stale timeout
+func (u *S3Uploader) abortStaleUploads(client *s3.Client, state *uploader.State) {
+	if u.config.StaleTimeout <= 0 {
+		return
+	}
+
+	if state.IsResumed() && time.Since(state.Started) > u.config.StaleTimeout {
+		u.logger.Warn("Upload state is stale, previously uploaded data will be discarded")
+		state.Reset()
+	}
+
+	input := &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(u.config.Bucket),
+		Prefix: aws.String(u.config.Path + "/"),
+	}
+
+	for {
+		resp, err := client.ListMultipartUploads(context.TODO(), input)
+
+		if err != nil {
+			u.logger.Error("Can't list multipart uploads: %v", err)
+			return
+		}
+
+		for _, upload := range resp.Uploads {
+			uploadID := aws.ToString(upload.UploadId)
+
+			if uploadID == state.UploadID ||
+				time.Since(aws.ToTime(upload.Initiated)) < u.config.StaleTimeout {
+				continue
+			}
+
+			u.logger.Info(
+				"Aborting stale multipart upload",
+				log.F{"upload-id", uploadID},
+				log.F{"upload-key", aws.ToString(upload.Key)},
+			)
+
+			_, err = client.AbortMultipartUpload(context.TODO(), &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(u.config.Bucket),
+				Key:      upload.Key,
+				UploadId: upload.UploadId,
+			})
+
+			if err != nil {
+				u.logger.Error("Can't abort multipart upload %s: %v", uploadID, err)
+			}
+		}
+
+		if !aws.ToBool(resp.IsTruncated) {
+			return
+		}
+
+		input.KeyMarker = resp.NextKeyMarker
+		input.UploadIdMarker = resp.NextUploadIdMarker
+	}
+}
+
+// writeMetadata writes backup metadata file
+func (u *S3Uploader) writeMetadata(client *s3.Client, meta *uploader.Metadata) error {
+	data, err := meta.Encode()
+
+	if err != nil {
+		return err
+	}
+
+	return u.putObject(client, meta.File+uploader.META_EXT, data)
+}
+
+// putObject writes JSON data to object in bucket
+func (u *S3Uploader) putObject(client *s3.Client, fileName string, data []byte) error {
+	_, err := client.PutObject(context.TODO(), &s3.PutObjectInput{
+		Bucket:      aws.String(u.config.Bucket),
+		Key:         aws.String(u.getOutputFile(fileName)),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	})
+
+	return err
+}
+
+// getState returns upload state if resuming is supported for current configuration
+func (u *S3Uploader) getState(file, fileName string) (*uploader.State, error) {
+	switch {
+	case u.config.StateDir == "":
+		return nil, nil
+	case u.config.Encryptor != nil:
+		return nil, uploader.ErrResumeEncrypted
+	}
+
+	return uploader.NewState(
+		u.config.StateDir, file,
+		"s3:"+u.config.Host+"/"+u.config.Bucket+"/"+u.getOutputFile(fileName),
+		u.config.Decryptor,
+	)
+}
+
+// getOutputFile returns path to output file in bucket
+func (u *S3Uploader) getOutputFile(fileName string) string {
+	if u.config.Path == "" {
+		return fileName
+	}
+
+	return path.Join(u.config.Path, fileName)
+}
+
+// getClient returns S3 client
+func (u *S3Uploader) getClient() *s3.Client {
+	return s3.New(s3.Options{
+		Region:       u.config.Region,
+		BaseEndpoint: aws.String("https://" + u.config.Host),
+		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(
+			u.config.AccessKeyID, u.config.SecretKey, "",
+		)),
+	})
+}
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 // Validate validates configuration
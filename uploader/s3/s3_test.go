@@ -0,0 +1,66 @@
+package s3
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func TestIsUploadExist(t *testing.T) {
+	var status int
+	var body string
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/xml")
+		rw.WriteHeader(status)
+		rw.Write([]byte(body))
+	}))
+
+	defer server.Close()
+
+	u := &S3Uploader{config: &Config{Bucket: "backups"}}
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Retryer:      aws.NopRetryer{},
+		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(
+			"key", "secret", "",
+		)),
+	})
+
+	status, body = 200, `<ListPartsResult><UploadId>upload-1</UploadId></ListPartsResult>`
+	isExist, err := u.isUploadExist(client, "backup.zip", "upload-1")
+
+	if err != nil || !isExist {
+		t.Fatalf("Upload must exist (%t): %v", isExist, err)
+	}
+
+	status, body = 404, `<Error><Code>NoSuchUpload</Code><Message>Not found</Message></Error>`
+	isExist, err = u.isUploadExist(client, "backup.zip", "upload-1")
+
+	if err != nil || isExist {
+		t.Fatalf("Upload must not exist (%t): %v", isExist, err)
+	}
+
+	// Temporary errors must not be treated as missing upload
+	status, body = 503, `<Error><Code>SlowDown</Code><Message>Slow down</Message></Error>`
+	isExist, err = u.isUploadExist(client, "backup.zip", "upload-1")
+
+	if err == nil || isExist {
+		t.Fatalf("Error must be returned (%t)", isExist)
+	}
+}
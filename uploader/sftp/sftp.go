@@ -8,6 +8,8 @@ package sftp
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -15,6 +17,7 @@ import (
 	"time"
 
 	"github.com/essentialkaos/ek/v13/events"
+	"github.com/essentialkaos/ek/v13/fmtutil"
 	"github.com/essentialkaos/ek/v13/fsutil"
 	"github.com/essentialkaos/ek/v13/log"
 	"github.com/essentialkaos/ek/v13/passthru"
@@ -23,8 +26,7 @@ import (
 	"github.com/pkg/sftp"
 	"golang.org/x/crypto/ssh"
 
-	"github.com/essentialkaos/katana"
-
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor"
 	"github.com/essentialkaos/atlassian-cloud-backuper/uploader"
 )
 
@@ -32,18 +34,22 @@ import (
 
 // Config is configuration for SFTP uploader
 type Config struct {
-	Secret *katana.Secret
-	Host   string
-	User   string
-	Key    []byte
-	Path   string
-	Mode   os.FileMode
+	Encryptor encryptor.Encryptor
+	Decryptor encryptor.Decryptor
+	Host      string
+	User      string
+	Key       []byte
+	Path      string
+	StateDir  string
+	Version   string
+	Mode      os.FileMode
 }
 
 // SFTPUploader is SFTP uploader instance
 type SFTPUploader struct {
 	config     *Config
 	dispatcher *events.Dispatcher
+	logger     log.ILogger
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -61,7 +67,7 @@ func NewUploader(config *Config) (*SFTPUploader, error) {
 		return nil, err
 	}
 
-	return &SFTPUploader{config: config}, nil
+	return &SFTPUploader{config: config, logger: log.Global}, nil
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -73,9 +79,22 @@ func (u *SFTPUploader) SetDispatcher(d *events.Dispatcher) {
 	}
 }
 
+// SetLogger sets logger for process messages
+func (u *SFTPUploader) SetLogger(l log.ILogger) {
+	if u == nil {
+		return
+	}
+
+	if l == nil {
+		l = log.Global
+	}
+
+	u.logger = l
+}
+
 // Upload uploads given file to SFTP storage
 func (u *SFTPUploader) Upload(file, fileName string) error {
-	fd, err := os.Open(file)
+	fd, err := uploader.OpenSource(file, u.config.Decryptor)
 
 	if err != nil {
 		return fmt.Errorf("Can't open backup file for reading: %v", err)
@@ -83,17 +102,151 @@ func (u *SFTPUploader) Upload(file, fileName string) error {
 
 	defer fd.Close()
 
-	err = u.Write(fd, fileName, fsutil.GetSize(file))
+	state, err := u.getState(file, fileName)
 
 	if err != nil {
+		u.logger.Warn("Upload resuming is disabled: %v", err)
+	}
+
+	// Size of encrypted source file differs from size of its data, so exact
+	// size from state is used if possible
+	size := fsutil.GetSize(file)
+
+	if state != nil {
+		size = state.Size
+	}
+
+	err = u.write(fd, fileName, size, state)
+
+	if err != nil {
+		state.Save()
 		return fmt.Errorf("Can't save backup: %w", err)
 	}
 
+	state.Delete()
+
 	return nil
 }
 
 // Write writes data from given reader to given file
 func (u *SFTPUploader) Write(r io.ReadCloser, fileName string, fileSize int64) error {
+	return u.write(r, fileName, fileSize, nil)
+}
+
+// Check checks storage availability
+func (u *SFTPUploader) Check() error {
+	sftpClient, err := u.connectToSFTP()
+
+	if err != nil {
+		return fmt.Errorf("Can't connect to SFTP: %v", err)
+	}
+
+	defer sftpClient.Close()
+
+	_, err = sftpClient.Getwd()
+
+	if err != nil {
+		return fmt.Errorf("Can't get working directory: %w", err)
+	}
+
+	return nil
+}
+
+// List returns info about all files in storage
+func (u *SFTPUploader) List() ([]*uploader.FileInfo, error) {
+	sftpClient, err := u.connectToSFTP()
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't connect to SFTP: %v", err)
+	}
+
+	defer sftpClient.Close()
+
+	entries, err := sftpClient.ReadDir(u.config.Path)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var result []*uploader.FileInfo
+
+	for _, info := range entries {
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		result = append(result, &uploader.FileInfo{
+			Name:     info.Name(),
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+		})
+	}
+
+	return result, nil
+}
+
+// ReadFile reads small file (metadata or catalog) from storage
+func (u *SFTPUploader) ReadFile(fileName string) ([]byte, error) {
+	sftpClient, err := u.connectToSFTP()
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't connect to SFTP: %v", err)
+	}
+
+	defer sftpClient.Close()
+
+	fd, err := sftpClient.Open(path.Join(u.config.Path, fileName))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer fd.Close()
+
+	return io.ReadAll(fd)
+}
+
+// WriteFile writes small file (metadata or catalog) to storage
+func (u *SFTPUploader) WriteFile(fileName string, data []byte) error {
+	sftpClient, err := u.connectToSFTP()
+
+	if err != nil {
+		return fmt.Errorf("Can't connect to SFTP: %v", err)
+	}
+
+	defer sftpClient.Close()
+
+	err = sftpClient.MkdirAll(u.config.Path)
+
+	if err != nil {
+		return fmt.Errorf("Can't create directory for backup: %v", err)
+	}
+
+	return u.writeFile(sftpClient, fileName, data)
+}
+
+// DeleteFile removes file from storage
+func (u *SFTPUploader) DeleteFile(fileName string) error {
+	sftpClient, err := u.connectToSFTP()
+
+	if err != nil {
+		return fmt.Errorf("Can't connect to SFTP: %v", err)
+	}
+
+	defer sftpClient.Close()
+
+	return sftpClient.Remove(path.Join(u.config.Path, fileName))
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// write writes data from given reader to given file and resumes previously
+// interrupted uploading if state is provided
+func (u *SFTPUploader) write(r io.Reader, fileName string, fileSize int64, state *uploader.State) error {
 	u.dispatcher.DispatchAndWait(uploader.EVENT_UPLOAD_STARTED, "SFTP")
 
 	var w io.Writer
@@ -101,7 +254,7 @@ func (u *SFTPUploader) Write(r io.ReadCloser, fileName string, fileSize int64) e
 	lastUpdate := time.Now()
 	outputFile := path.Join(u.config.Path, fileName)
 
-	log.Info(
+	u.logger.Info(
 		"Uploading backup file to %s@%s~%s/%s…",
 		u.config.User, u.config.Host, u.config.Path, fileName,
 	)
@@ -124,28 +277,48 @@ func (u *SFTPUploader) Write(r io.ReadCloser, fileName string, fileSize int64) e
 		}
 	}
 
-	fd, err := sftpClient.OpenFile(outputFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY)
+	fd, offset, err := u.openOutputFile(sftpClient, outputFile, state)
 
 	if err != nil {
 		return fmt.Errorf("Can't create file of SFTP: %v", err)
 	}
 
+	defer fd.Close()
+
+	hasher := sha256.New()
+
+	if offset > 0 {
+		// Already uploaded data is read through hasher instead of seeking, so
+		// checksum covers the whole file
+		_, err = io.CopyN(hasher, r, offset)
+
+		if err != nil {
+			return fmt.Errorf("Can't read backup file: %w", err)
+		}
+
+		u.logger.Info("Resuming interrupted uploading from %s", fmtutil.PrettySize(offset))
+	}
+
 	w = fd
 
-	if u.config.Secret != nil {
-		sw, err := u.config.Secret.NewWriter(fd)
+	if state != nil {
+		w = uploader.NewStateWriter(fd, state)
+	}
+
+	var ew io.WriteCloser
+
+	if u.config.Encryptor != nil {
+		ew, err = u.config.Encryptor.NewWriter(w)
 
 		if err != nil {
 			return fmt.Errorf("Can't create encrypted writer: %w", err)
 		}
 
-		defer sw.Close()
-
-		w = sw
+		w = ew
 	}
 
 	if fileSize > 0 {
-		pw := passthru.NewWriter(w, fileSize)
+		pw := passthru.NewWriter(w, fileSize-offset)
 
 		pw.Update = func(n int) {
 			if time.Since(lastUpdate) < 3*time.Second {
@@ -155,9 +328,9 @@ func (u *SFTPUploader) Write(r io.ReadCloser, fileName string, fileSize int64) e
 			u.dispatcher.Dispatch(
 				uploader.EVENT_UPLOAD_PROGRESS,
 				&uploader.ProgressInfo{
-					Progress: pw.Progress(),
-					Current:  pw.Current(),
-					Total:    pw.Total(),
+					Progress: float64(offset+pw.Current()) / float64(fileSize) * 100,
+					Current:  offset + pw.Current(),
+					Total:    fileSize,
 				},
 			)
 
@@ -167,25 +340,167 @@ func (u *SFTPUploader) Write(r io.ReadCloser, fileName string, fileSize int64) e
 		w = pw
 	}
 
-	_, err = io.Copy(w, r)
+	n, err := io.Copy(w, io.TeeReader(r, hasher))
 
 	if err != nil {
+		if state == nil {
+			// Partial file can't be resumed without state, so we remove it
+			sftpClient.Remove(outputFile)
+		}
+
 		return fmt.Errorf("Can't upload file to SFTP: %v", err)
 	}
 
+	// Encrypting writer writes the final chunk and authentication tag on close,
+	// and remote file is complete only after successful closing
+	if ew != nil {
+		err = ew.Close()
+	}
+
+	if err == nil {
+		err = fd.Close()
+
+		// Some encrypting writers close underlying writer by themselves
+		if errors.Is(err, os.ErrClosed) {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		if state == nil {
+			sftpClient.Remove(outputFile)
+		}
+
+		return fmt.Errorf("Can't finalize file on SFTP: %w", err)
+	}
+
 	err = sftpClient.Chmod(outputFile, u.config.Mode)
 
 	if err != nil {
-		log.Error("Can't change file mode for uploaded file: %v", err)
+		u.logger.Error("Can't change file mode for uploaded file: %v", err)
+	}
+
+	meta := uploader.NewMetadata(fileName, offset+n, u.config.Encryptor)
+	meta.Checksum = uploader.FormatChecksum(hasher)
+	meta.Version = u.config.Version
+
+	err = u.writeMetadata(sftpClient, meta)
+
+	if err != nil {
+		if u.config.Encryptor != nil {
+			// Metadata contains info required for decrypting backup, so encrypted
+			// backup without metadata is removed and uploading is considered failed
+			sftpClient.Remove(outputFile)
+			return fmt.Errorf("Can't save backup metadata: %w", err)
+		}
+
+		u.logger.Error("Can't save backup metadata: %v", err)
 	}
 
-	log.Info("File successfully uploaded to SFTP!")
+	err = uploader.UpdateCatalog(u, meta)
+
+	if err != nil {
+		u.logger.Error("Can't update backup catalog: %v", err)
+	}
+
+	u.logger.Info("File successfully uploaded to SFTP!")
 	u.dispatcher.DispatchAndWait(uploader.EVENT_UPLOAD_DONE, "SFTP")
 
 	return nil
 }
 
-// ////////////////////////////////////////////////////////////////////////////////// //
+// openOutputFile opens file on SFTP for writing and returns offset for resuming
+// interrupted uploading
+func (u *SFTPUploader) openOutputFile(sftpClient *sftp.Client, outputFile string, state *uploader.State) (*sftp.File, int64, error) {
+	var remoteSize int64
+
+	if state.IsResumed() {
+		info, err := sftpClient.Stat(outputFile)
+
+		if err == nil {
+			remoteSize = info.Size()
+		}
+	}
+
+	if !state.IsResumed() || remoteSize < state.Offset {
+		state.Reset()
+
+		fd, err := sftpClient.OpenFile(outputFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY)
+
+		return fd, 0, err
+	}
+
+	fd, err := sftpClient.OpenFile(outputFile, os.O_WRONLY)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = fd.Truncate(state.Offset)
+
+	if err == nil {
+		_, err = fd.Seek(state.Offset, io.SeekStart)
+	}
+
+	if err != nil {
+		fd.Close()
+		return nil, 0, fmt.Errorf("Can't prepare file for resuming: %w", err)
+	}
+
+	return fd, state.Offset, nil
+}
+
+// writeMetadata writes backup metadata file
+func (u *SFTPUploader) writeMetadata(sftpClient *sftp.Client, meta *uploader.Metadata) error {
+	data, err := meta.Encode()
+
+	if err != nil {
+		return err
+	}
+
+	return u.writeFile(sftpClient, meta.File+uploader.META_EXT, data)
+}
+
+// writeFile writes data to file in storage
+func (u *SFTPUploader) writeFile(sftpClient *sftp.Client, fileName string, data []byte) error {
+	file := path.Join(u.config.Path, fileName)
+	fd, err := sftpClient.OpenFile(file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fd.Write(data)
+
+	if err != nil {
+		fd.Close()
+		return err
+	}
+
+	err = fd.Close()
+
+	if err != nil {
+		return err
+	}
+
+	return sftpClient.Chmod(file, u.config.Mode)
+}
+
+// getState returns upload state if resuming is supported for current configuration
+func (u *SFTPUploader) getState(file, fileName string) (*uploader.State, error) {
+	switch {
+	case u.config.StateDir == "":
+		return nil, nil
+	case u.config.Encryptor != nil:
+		return nil, uploader.ErrResumeEncrypted
+	}
+
+	return uploader.NewState(
+		u.config.StateDir, file,
+		"sftp:"+u.config.User+"@"+u.config.Host+":"+path.Join(u.config.Path, fileName),
+		u.config.Decryptor,
+	)
+}
 
 // connectToSFTP connects to SFTP storage
 func (u *SFTPUploader) connectToSFTP() (*sftp.Client, error) {
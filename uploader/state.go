@@ -0,0 +1,267 @@
+package uploader
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/fsutil"
+	"github.com/essentialkaos/ek/v13/hashutil"
+	"github.com/essentialkaos/ek/v13/jsonutil"
+	"github.com/essentialkaos/ek/v13/path"
+
+	"github.com/essentialkaos/atlassian-cloud-backuper/encryptor"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// STATE_HEAD_SIZE is size of source file head used for source identification
+const STATE_HEAD_SIZE = 1024 * 1024
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ErrResumeEncrypted is returned if upload state is requested for encrypted upload.
+// Encrypted data can't be continued from offset, because every encrypted stream uses
+// its own random key or nonce.
+var ErrResumeEncrypted = errors.New("resuming is not supported for encrypted uploads")
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// State contains info about interrupted upload which can be used for resuming
+type State struct {
+	Target   string    `json:"target"`
+	Size     int64     `json:"size"`
+	Checksum string    `json:"checksum"`
+	Offset   int64     `json:"offset,omitempty"`
+	UploadID string    `json:"upload_id,omitempty"`
+	Parts    []*Part   `json:"parts,omitempty"`
+	Started  time.Time `json:"started"`
+	Updated  time.Time `json:"updated"`
+
+	file string
+}
+
+// Part contains info about uploaded part of multipart upload
+type Part struct {
+	Num  int32  `json:"num"`
+	ETag string `json:"etag"`
+	Size int64  `json:"size"`
+}
+
+// sourceReader is reader of encrypted source file
+type sourceReader struct {
+	r  io.Reader
+	fd *os.File
+}
+
+// StateWriter is writer which tracks offset of written data in upload state
+type StateWriter struct {
+	w         io.Writer
+	state     *State
+	lastSaved time.Time
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewState creates new upload state for given source file and upload target or
+// loads previously saved state if it was created for the same source file.
+// If decryptor is set, source file is identified by decrypted data, so state
+// can be used for the same data encrypted with another key.
+func NewState(dir, source, target string, dec encryptor.Decryptor) (*State, error) {
+	err := fsutil.ValidatePerms("DWX", dir)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't use directory for upload state: %w", err)
+	}
+
+	size, checksum, err := getSourceInfo(source, dec)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't calculate source file checksum: %w", err)
+	}
+
+	state := &State{}
+	stateFile := path.Join(dir, hashutil.String(target, sha256.New()).String()[:32]+".json")
+
+	if fsutil.IsExist(stateFile) {
+		err = jsonutil.Read(stateFile, state)
+
+		if err != nil {
+			os.Remove(stateFile)
+			state = &State{}
+		}
+	}
+
+	if state.Target != target || state.Size != size || state.Checksum != checksum {
+		state = &State{
+			Target:   target,
+			Size:     size,
+			Checksum: checksum,
+			Started:  time.Now(),
+		}
+	}
+
+	state.file = stateFile
+
+	return state, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// IsResumed returns true if state contains info about interrupted upload
+func (s *State) IsResumed() bool {
+	return s != nil && (s.Offset > 0 || s.UploadID != "")
+}
+
+// Uploaded returns size of data uploaded as parts
+func (s *State) Uploaded() int64 {
+	var size int64
+
+	if s == nil {
+		return 0
+	}
+
+	for _, p := range s.Parts {
+		size += p.Size
+	}
+
+	return size
+}
+
+// AddPart adds info about uploaded part
+func (s *State) AddPart(num int32, etag string, size int64) {
+	if s != nil {
+		s.Parts = append(s.Parts, &Part{num, etag, size})
+	}
+}
+
+// Reset resets upload progress info
+func (s *State) Reset() {
+	if s == nil {
+		return
+	}
+
+	s.Offset, s.UploadID, s.Parts = 0, "", nil
+	s.Started = time.Now()
+}
+
+// Save saves state to file
+func (s *State) Save() error {
+	if s == nil {
+		return nil
+	}
+
+	s.Updated = time.Now()
+
+	return jsonutil.Write(s.file, s, 0600)
+}
+
+// Delete removes state file
+func (s *State) Delete() error {
+	if s == nil || !fsutil.IsExist(s.file) {
+		return nil
+	}
+
+	return os.Remove(s.file)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewStateWriter creates new writer which tracks written data offset
+func NewStateWriter(w io.Writer, state *State) *StateWriter {
+	return &StateWriter{w: w, state: state, lastSaved: time.Now()}
+}
+
+// Write writes data to underlying writer and updates offset in state
+func (w *StateWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+
+	w.state.Offset += int64(n)
+
+	if time.Since(w.lastSaved) >= 5*time.Second {
+		w.state.Save()
+		w.lastSaved = time.Now()
+	}
+
+	return n, err
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// OpenSource opens source file for reading. If decryptor is set, data is
+// decrypted on the fly.
+func OpenSource(file string, dec encryptor.Decryptor) (io.ReadCloser, error) {
+	fd, err := os.Open(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if dec == nil {
+		return fd, nil
+	}
+
+	r, err := dec.NewDecryptReader(bufio.NewReader(fd))
+
+	if err != nil {
+		fd.Close()
+		return nil, fmt.Errorf("Can't create decrypting reader: %w", err)
+	}
+
+	return &sourceReader{r, fd}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Read reads decrypted data
+func (r *sourceReader) Read(p []byte) (int, error) {
+	return r.r.Read(p)
+}
+
+// Close closes source file
+func (r *sourceReader) Close() error {
+	return r.fd.Close()
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getSourceInfo returns size of source data and SHA-256 checksum of its head.
+// Encrypted source is read completely for calculating size of decrypted data.
+func getSourceInfo(file string, dec encryptor.Decryptor) (int64, string, error) {
+	r, err := OpenSource(file, dec)
+
+	if err != nil {
+		return 0, "", err
+	}
+
+	defer r.Close()
+
+	head, hash, err := hashutil.Copy(io.Discard, io.LimitReader(r, STATE_HEAD_SIZE), sha256.New())
+
+	if err != nil {
+		return 0, "", err
+	}
+
+	if dec == nil {
+		return fsutil.GetSize(file), hash.String(), nil
+	}
+
+	n, err := io.Copy(io.Discard, r)
+
+	if err != nil {
+		return 0, "", err
+	}
+
+	return head + n, hash.String(), nil
+}
@@ -0,0 +1,235 @@
+package uploader
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/essentialkaos/ek/v13/path"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func TestStateRestore(t *testing.T) {
+	dir := t.TempDir()
+	source := createSourceFile(t, dir, "backup.zip", 4096)
+
+	state, err := NewState(dir, source, "fs:/backups/backup.zip", nil)
+
+	if err != nil {
+		t.Fatalf("Can't create state: %v", err)
+	}
+
+	if state.IsResumed() {
+		t.Fatal("New state marked as resumed")
+	}
+
+	state.Offset = 1024
+
+	if state.Save() != nil {
+		t.Fatal("Can't save state")
+	}
+
+	restored, err := NewState(dir, source, "fs:/backups/backup.zip", nil)
+
+	if err != nil {
+		t.Fatalf("Can't restore state: %v", err)
+	}
+
+	if !restored.IsResumed() || restored.Offset != 1024 {
+		t.Fatalf("State is not restored (offset: %d)", restored.Offset)
+	}
+
+	other, err := NewState(dir, source, "fs:/backups/other.zip", nil)
+
+	if err != nil {
+		t.Fatalf("Can't create state: %v", err)
+	}
+
+	if other.IsResumed() || other.file == restored.file {
+		t.Fatal("State for other target must use another file")
+	}
+
+	if restored.Delete() != nil || fileExists(restored.file) {
+		t.Fatal("State file is not removed")
+	}
+}
+
+func TestStateSourceChange(t *testing.T) {
+	dir := t.TempDir()
+	source := createSourceFile(t, dir, "backup.zip", 4096)
+
+	state, _ := NewState(dir, source, "s3:host/bucket/backup.zip", nil)
+	state.UploadID = "upload-1"
+	state.AddPart(1, "etag-1", 2048)
+	state.Save()
+
+	// Same size but different data
+	os.WriteFile(source, bytes.Repeat([]byte("B"), 4096), 0600)
+
+	state, _ = NewState(dir, source, "s3:host/bucket/backup.zip", nil)
+
+	if state.IsResumed() || len(state.Parts) != 0 {
+		t.Fatal("State for changed source must not be resumed")
+	}
+
+	state.Offset = 1024
+	state.Save()
+
+	// Different size
+	createSourceFile(t, dir, "backup.zip", 8192)
+
+	state, _ = NewState(dir, source, "s3:host/bucket/backup.zip", nil)
+
+	if state.IsResumed() || state.Size != 8192 {
+		t.Fatal("State for changed source must not be resumed")
+	}
+}
+
+func TestStateEncryptedSource(t *testing.T) {
+	dir := t.TempDir()
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	source := path.Join(dir, "backup.enc")
+
+	// The same data encrypted with another key (e.g. after restart with
+	// ephemeral key) must be identified as the same source
+	os.WriteFile(source, append([]byte("header-1"), data...), 0600)
+
+	state, err := NewState(dir, source, "fs:/backups/backup.zip", testDecryptor{})
+
+	if err != nil {
+		t.Fatalf("Can't create state: %v", err)
+	}
+
+	if state.Size != int64(len(data)) {
+		t.Fatalf("Invalid source size %d", state.Size)
+	}
+
+	state.Offset = 1024
+	state.Save()
+
+	os.WriteFile(source, append([]byte("header-2"), data...), 0600)
+
+	state, err = NewState(dir, source, "fs:/backups/backup.zip", testDecryptor{})
+
+	if err != nil {
+		t.Fatalf("Can't restore state: %v", err)
+	}
+
+	if state.Offset != 1024 {
+		t.Fatal("State for re-encrypted source is not restored")
+	}
+
+	r, err := OpenSource(source, testDecryptor{})
+
+	if err != nil {
+		t.Fatalf("Can't open source: %v", err)
+	}
+
+	defer r.Close()
+
+	result, _ := io.ReadAll(r)
+
+	if !bytes.Equal(result, data) {
+		t.Fatal("Source data is not decrypted")
+	}
+}
+
+func TestStateBrokenFile(t *testing.T) {
+	dir := t.TempDir()
+	source := createSourceFile(t, dir, "backup.zip", 100)
+
+	state, _ := NewState(dir, source, "fs:/backups/backup.zip", nil)
+	os.WriteFile(state.file, []byte("{broken"), 0600)
+
+	state, err := NewState(dir, source, "fs:/backups/backup.zip", nil)
+
+	if err != nil {
+		t.Fatalf("Broken state must be ignored: %v", err)
+	}
+
+	if state.IsResumed() || fileExists(state.file) {
+		t.Fatal("Broken state must be removed")
+	}
+}
+
+func TestStateReset(t *testing.T) {
+	state := &State{Offset: 100, UploadID: "upload-1"}
+	state.AddPart(1, "etag-1", 60)
+	state.AddPart(2, "etag-2", 40)
+
+	if state.Uploaded() != 100 {
+		t.Fatalf("Invalid uploaded size %d", state.Uploaded())
+	}
+
+	state.Reset()
+
+	if state.IsResumed() || state.Uploaded() != 0 || state.Started.IsZero() {
+		t.Fatal("State is not reset")
+	}
+}
+
+func TestStateNil(t *testing.T) {
+	var state *State
+
+	state.Reset()
+	state.AddPart(1, "etag-1", 10)
+
+	if state.IsResumed() || state.Uploaded() != 0 {
+		t.Fatal("Nil state must not be resumed")
+	}
+
+	if state.Save() != nil || state.Delete() != nil {
+		t.Fatal("Nil state must be ignored")
+	}
+}
+
+func TestStateWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	state := &State{Offset: 10, file: path.Join(t.TempDir(), "state.json")}
+	w := NewStateWriter(&buf, state)
+
+	w.Write([]byte("12345"))
+	w.Write([]byte("678"))
+
+	if state.Offset != 18 || buf.String() != "12345678" {
+		t.Fatalf("Invalid offset %d", state.Offset)
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// createSourceFile creates file with given size in given directory
+func createSourceFile(t *testing.T, dir, name string, size int) string {
+	file := path.Join(dir, name)
+	err := os.WriteFile(file, bytes.Repeat([]byte("A"), size), 0600)
+
+	if err != nil {
+		t.Fatalf("Can't create source file: %v", err)
+	}
+
+	return file
+}
+
+// testDecryptor is decryptor which skips fixed size header
+type testDecryptor struct{}
+
+func (d testDecryptor) NewDecryptReader(r io.Reader) (io.Reader, error) {
+	_, err := io.CopyN(io.Discard, r, 8)
+	return r, err
+}
+
+// fileExists returns true if given file exists
+func fileExists(file string) bool {
+	_, err := os.Stat(file)
+	return err == nil
+}
@@ -9,8 +9,10 @@ package uploader
 
 import (
 	"io"
+	"time"
 
 	"github.com/essentialkaos/ek/v13/events"
+	"github.com/essentialkaos/ek/v13/log"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -24,9 +26,16 @@ const (
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 type ProgressInfo struct {
-	Progress float64
-	Current  int64
-	Total    int64
+	Progress float64 `json:"progress"`
+	Current  int64   `json:"current"`
+	Total    int64   `json:"total"`
+}
+
+// FileInfo contains basic info about file in storage
+type FileInfo struct {
+	Name     string
+	Size     int64
+	Modified time.Time
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -36,11 +45,29 @@ type Uploader interface {
 	// SetDispatcher sets events dispatcher
 	SetDispatcher(d *events.Dispatcher)
 
+	// SetLogger sets logger for process messages
+	SetLogger(l log.ILogger)
+
 	// Upload uploads given file to storage
 	Upload(file, fileName string) error
 
 	// Write writes data from given reader to given file
 	Write(r io.ReadCloser, fileName string, fileSize int64) error
+
+	// Check checks storage availability
+	Check() error
+
+	// List returns info about all files in storage
+	List() ([]*FileInfo, error)
+
+	// ReadFile reads small file (metadata or catalog) from storage
+	ReadFile(fileName string) ([]byte, error)
+
+	// WriteFile writes small file (metadata or catalog) to storage
+	WriteFile(fileName string, data []byte) error
+
+	// DeleteFile removes file from storage
+	DeleteFile(fileName string) error
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //